@@ -1,11 +1,35 @@
 package main
 
 import (
+	"flag"
 	"fmt"
+	"os"
+
 	"rss-reader/internal/app"
+	"rss-reader/internal/cli"
+	"rss-reader/internal/config"
 )
 
 func main() {
+	fs := flag.NewFlagSet("rss-reader", flag.ContinueOnError)
+	configPath := fs.String("config", "", "config file path")
+	if err := fs.Parse(os.Args[1:]); err != nil {
+		os.Exit(2)
+	}
+
+	if err := config.Load(config.Path(*configPath)); err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+
+	if args := fs.Args(); len(args) > 0 {
+		if err := cli.Run(args); err != nil {
+			fmt.Fprintf(os.Stderr, "%v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	application := app.New()
 	if err := application.Run(); err != nil {
 		fmt.Printf("Failed to launch: %v", err)
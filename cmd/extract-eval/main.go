@@ -0,0 +1,71 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"rss-reader/internal/util"
+)
+
+// 以 testdata 內保存的頁面與預期正文衡量擷取品質，
+// 同一組頁面也由 internal/util 的測試檢查
+func main() {
+	dir := flag.String("dir", "testdata/extract", "directory with <name>.html and <name>.txt pairs")
+	threshold := flag.Float64("min", 0.9, "minimum average F1 before exiting with an error")
+	verbose := flag.Bool("v", false, "print extracted content of each page")
+	flag.Parse()
+
+	pages, err := filepath.Glob(filepath.Join(*dir, "*.html"))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	if len(pages) == 0 {
+		fmt.Fprintf(os.Stderr, "no pages in %s\n", *dir)
+		os.Exit(1)
+	}
+	sort.Strings(pages)
+
+	extractor := util.NewExtractor()
+	total := 0.0
+
+	fmt.Printf("%-24s %9s %9s %9s\n", "page", "precision", "recall", "f1")
+	for _, page := range pages {
+		name := strings.TrimSuffix(filepath.Base(page), ".html")
+
+		expected, err := os.ReadFile(strings.TrimSuffix(page, ".html") + ".txt")
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %v\n", name, err)
+			os.Exit(1)
+		}
+
+		f, err := os.Open(page)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %v\n", name, err)
+			os.Exit(1)
+		}
+		extracted, err := extractor.Parse(f, "")
+		f.Close()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %v\n", name, err)
+			os.Exit(1)
+		}
+
+		precision, recall, f1 := util.ScoreExtraction(extracted.Content, string(expected))
+		total += f1
+		fmt.Printf("%-24s %9.3f %9.3f %9.3f\n", name, precision, recall, f1)
+		if *verbose {
+			fmt.Printf("\n%s\n\n", extracted.Content)
+		}
+	}
+
+	average := total / float64(len(pages))
+	fmt.Printf("%-24s %29.3f\n", "average", average)
+	if average < *threshold {
+		os.Exit(1)
+	}
+}
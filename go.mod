@@ -3,10 +3,12 @@ module rss-reader
 go 1.24.3
 
 require (
+	github.com/BurntSushi/toml v1.6.0
 	github.com/PuerkitoBio/goquery v1.10.3
 	github.com/gdamore/tcell/v2 v2.8.1
 	github.com/mattn/go-sqlite3 v1.14.28
 	github.com/rivo/tview v0.0.0-20250625164341-a4a78f1e05cb
+	golang.org/x/net v0.39.0
 )
 
 require (
@@ -15,7 +17,6 @@ require (
 	github.com/lucasb-eyer/go-colorful v1.2.0 // indirect
 	github.com/mattn/go-runewidth v0.0.16 // indirect
 	github.com/rivo/uniseg v0.4.7 // indirect
-	golang.org/x/net v0.39.0 // indirect
 	golang.org/x/sys v0.32.0 // indirect
 	golang.org/x/term v0.31.0 // indirect
 	golang.org/x/text v0.24.0 // indirect
@@ -0,0 +1,116 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+)
+
+const (
+	anthropicVersion = "2023-06-01"
+	// Messages API 必須指定輸出上限
+	anthropicMaxTokens = 4096
+)
+
+// Anthropic Messages API
+type Anthropic struct {
+	BaseURL string
+	Key     string
+}
+
+type anthropicRequest struct {
+	Model     string    `json:"model"`
+	System    string    `json:"system,omitempty"`
+	Messages  []Message `json:"messages"`
+	MaxTokens int       `json:"max_tokens"`
+	Stream    bool      `json:"stream"`
+}
+
+type anthropicUsage struct {
+	InputTokens  int `json:"input_tokens"`
+	OutputTokens int `json:"output_tokens"`
+}
+
+type anthropicEvent struct {
+	Type    string `json:"type"`
+	Message struct {
+		Usage anthropicUsage `json:"usage"`
+	} `json:"message"`
+	Usage anthropicUsage `json:"usage"`
+	Error struct {
+		Type    string `json:"type"`
+		Message string `json:"message"`
+	} `json:"error"`
+	Delta struct {
+		Type string `json:"type"`
+		Text string `json:"text"`
+	} `json:"delta"`
+}
+
+func (p *Anthropic) Chat(ctx context.Context, model string, msgList []Message, onDelta func(string)) (string, Usage, error) {
+	system, messages := anthropicMessages(msgList)
+
+	header := http.Header{}
+	header.Set("x-api-key", p.Key)
+	header.Set("anthropic-version", anthropicVersion)
+
+	res, err := post(ctx, p.BaseURL+"/v1/messages", header, anthropicRequest{
+		Model:     model,
+		System:    system,
+		Messages:  messages,
+		MaxTokens: anthropicMaxTokens,
+		Stream:    true,
+	})
+	if err != nil {
+		return "", Usage{}, err
+	}
+	defer res.Body.Close()
+
+	// 輸入用量在 message_start，輸出用量在 message_delta
+	var usage Usage
+	var streamErr error
+	result, err := readStream(res.Body, true, func(data []byte) string {
+		var event anthropicEvent
+		if err := json.Unmarshal(data, &event); err != nil {
+			return ""
+		}
+		switch event.Type {
+		case "message_start":
+			usage.PromptTokens = event.Message.Usage.InputTokens
+		case "message_delta":
+			usage.CompletionTokens = event.Usage.OutputTokens
+		case "error":
+			streamErr = streamError(event.Error.Type, event.Error.Message)
+		}
+		if event.Type != "content_block_delta" || event.Delta.Type != "text_delta" {
+			return ""
+		}
+		return event.Delta.Text
+	}, onDelta)
+	if err != nil {
+		return "", usage, err
+	}
+	if streamErr != nil {
+		return "", usage, streamErr
+	}
+	return result, usage, nil
+}
+
+// system 訊息改放在 system 欄位，連續相同角色的訊息合併為一則
+func anthropicMessages(msgList []Message) (string, []Message) {
+	var system []string
+	var messages []Message
+	for _, msg := range msgList {
+		if msg.Role == "system" {
+			system = append(system, msg.Content)
+			continue
+		}
+		if n := len(messages); n > 0 && messages[n-1].Role == msg.Role {
+			messages[n-1].Content += "\n\n" + msg.Content
+			continue
+		}
+		messages = append(messages, msg)
+	}
+	return strings.Join(system, "\n\n"), messages
+}
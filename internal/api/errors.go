@@ -0,0 +1,138 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// LLM 錯誤分類，以 errors.Is 判斷
+var (
+	ErrAuth          = errors.New("authentication failed")
+	ErrRateLimit     = errors.New("rate limited")
+	ErrContextLength = errors.New("context length exceeded")
+	ErrServer        = errors.New("server error")
+	ErrRequest       = errors.New("request rejected")
+	ErrBudget        = errors.New("monthly budget exceeded")
+)
+
+// 服務回傳的錯誤
+type Error struct {
+	Kind       error
+	StatusCode int
+	Message    string
+	// 服務要求的等待時間，沒有時為 0
+	RetryAfter time.Duration
+}
+
+func (e *Error) Error() string {
+	if e.StatusCode == 0 {
+		return fmt.Sprintf("%v: %s", e.Kind, e.Message)
+	}
+	return fmt.Sprintf("%v (status %d): %s", e.Kind, e.StatusCode, e.Message)
+}
+
+func (e *Error) Unwrap() error {
+	return e.Kind
+}
+
+// 上下文過長時各家回傳的訊息
+var contextMarkers = []string{
+	"context_length_exceeded",
+	"maximum context length",
+	"prompt is too long",
+	"too many tokens",
+	"context window",
+}
+
+func newError(res *http.Response, body []byte) *Error {
+	message := errorMessage(body)
+	e := &Error{
+		StatusCode: res.StatusCode,
+		Message:    message,
+		RetryAfter: retryAfter(res.Header.Get("Retry-After")),
+	}
+
+	lower := strings.ToLower(message + " " + string(body))
+	switch {
+	case res.StatusCode == http.StatusUnauthorized || res.StatusCode == http.StatusForbidden:
+		e.Kind = ErrAuth
+	case res.StatusCode == http.StatusTooManyRequests:
+		e.Kind = ErrRateLimit
+	case containsAny(lower, contextMarkers):
+		e.Kind = ErrContextLength
+	// Anthropic 過載時回傳 529
+	case res.StatusCode >= 500:
+		e.Kind = ErrServer
+	default:
+		e.Kind = ErrRequest
+	}
+	return e
+}
+
+// 串流中途的錯誤事件，依類型分類
+func streamError(kind, message string) *Error {
+	e := &Error{Kind: ErrServer, Message: message}
+	switch {
+	case strings.Contains(kind, "rate_limit"):
+		e.Kind = ErrRateLimit
+	case strings.Contains(kind, "authentication") || strings.Contains(kind, "permission"):
+		e.Kind = ErrAuth
+	case containsAny(strings.ToLower(message), contextMarkers):
+		e.Kind = ErrContextLength
+	}
+	return e
+}
+
+// 取出 {"error":{"message":...}} 或 {"error":"..."} 中的訊息，無法解析時使用原始內容
+func errorMessage(body []byte) string {
+	var payload struct {
+		Error json.RawMessage `json:"error"`
+	}
+	if err := json.Unmarshal(body, &payload); err == nil && len(payload.Error) > 0 {
+		var detail struct {
+			Message string `json:"message"`
+		}
+		if err := json.Unmarshal(payload.Error, &detail); err == nil && detail.Message != "" {
+			return detail.Message
+		}
+		var text string
+		if err := json.Unmarshal(payload.Error, &text); err == nil && text != "" {
+			return text
+		}
+	}
+
+	message := strings.TrimSpace(string(body))
+	if runes := []rune(message); len(runes) > 200 {
+		message = string(runes[:200]) + "..."
+	}
+	return message
+}
+
+// Retry-After 可為秒數或 HTTP 日期
+func retryAfter(value string) time.Duration {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return time.Duration(max(seconds, 0)) * time.Second
+	}
+	if t, err := http.ParseTime(value); err == nil {
+		return max(time.Until(t), 0)
+	}
+	return 0
+}
+
+func containsAny(str string, markers []string) bool {
+	for _, marker := range markers {
+		if strings.Contains(str, marker) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,136 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestRetryAfter(t *testing.T) {
+	tests := []struct {
+		in   string
+		want time.Duration
+	}{
+		{"", 0},
+		{"  ", 0},
+		{"30", 30 * time.Second},
+		{" 5 ", 5 * time.Second},
+		{"-1", 0},
+		{"soon", 0},
+		{"Wed, 21 Oct 2015 07:28:00 GMT", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			if got := retryAfter(tt.in); got != tt.want {
+				t.Errorf("retryAfter(%q) = %v, want %v", tt.in, got, tt.want)
+			}
+		})
+	}
+
+	// HTTP 日期換算為距今的等待時間
+	future := time.Now().Add(time.Minute).UTC().Format(http.TimeFormat)
+	if got := retryAfter(future); got <= 50*time.Second || got > time.Minute {
+		t.Errorf("retryAfter(%q) = %v, want about 1m", future, got)
+	}
+}
+
+func TestNewError(t *testing.T) {
+	tests := []struct {
+		name       string
+		status     int
+		retryAfter string
+		body       string
+		kind       error
+		message    string
+		wait       time.Duration
+	}{
+		{
+			name:    "unauthorized",
+			status:  http.StatusUnauthorized,
+			body:    `{"error":{"message":"invalid api key","type":"authentication_error"}}`,
+			kind:    ErrAuth,
+			message: "invalid api key",
+		},
+		{
+			name:    "forbidden",
+			status:  http.StatusForbidden,
+			body:    `{"error":"forbidden"}`,
+			kind:    ErrAuth,
+			message: "forbidden",
+		},
+		{
+			name:       "rate limited",
+			status:     http.StatusTooManyRequests,
+			retryAfter: "12",
+			body:       `{"error":{"message":"slow down"}}`,
+			kind:       ErrRateLimit,
+			message:    "slow down",
+			wait:       12 * time.Second,
+		},
+		{
+			name:    "context length",
+			status:  http.StatusBadRequest,
+			body:    `{"error":{"message":"This model's maximum context length is 8192 tokens","code":"context_length_exceeded"}}`,
+			kind:    ErrContextLength,
+			message: "This model's maximum context length is 8192 tokens",
+		},
+		{
+			name:    "anthropic overloaded",
+			status:  529,
+			body:    `{"type":"error","error":{"type":"overloaded_error","message":"Overloaded"}}`,
+			kind:    ErrServer,
+			message: "Overloaded",
+		},
+		{
+			name:    "plain text body",
+			status:  http.StatusBadRequest,
+			body:    "bad request\n",
+			kind:    ErrRequest,
+			message: "bad request",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := &http.Response{StatusCode: tt.status, Header: http.Header{}}
+			if tt.retryAfter != "" {
+				res.Header.Set("Retry-After", tt.retryAfter)
+			}
+
+			e := newError(res, []byte(tt.body))
+			if !errors.Is(e, tt.kind) {
+				t.Errorf("kind = %v, want %v", e.Kind, tt.kind)
+			}
+			if e.StatusCode != tt.status {
+				t.Errorf("status = %d, want %d", e.StatusCode, tt.status)
+			}
+			if e.Message != tt.message {
+				t.Errorf("message = %q, want %q", e.Message, tt.message)
+			}
+			if e.RetryAfter != tt.wait {
+				t.Errorf("retry after = %v, want %v", e.RetryAfter, tt.wait)
+			}
+		})
+	}
+}
+
+func TestStreamError(t *testing.T) {
+	tests := []struct {
+		kind, message string
+		want          error
+	}{
+		{"rate_limit_error", "too many requests", ErrRateLimit},
+		{"authentication_error", "invalid x-api-key", ErrAuth},
+		{"permission_error", "not allowed", ErrAuth},
+		{"invalid_request_error", "prompt is too long: 210000 tokens", ErrContextLength},
+		{"overloaded_error", "Overloaded", ErrServer},
+		{"", "internal error", ErrServer},
+	}
+	for _, tt := range tests {
+		t.Run(tt.kind, func(t *testing.T) {
+			if e := streamError(tt.kind, tt.message); !errors.Is(e, tt.want) {
+				t.Errorf("streamError(%q, %q) = %v, want %v", tt.kind, tt.message, e.Kind, tt.want)
+			}
+		})
+	}
+}
@@ -2,11 +2,14 @@ package api
 
 import (
 	"bufio"
+	"bytes"
+	"context"
 	"encoding/json"
-	"fmt"
 	"io"
 	"net/http"
 	"strings"
+
+	"rss-reader/internal/config"
 )
 
 type Message struct {
@@ -14,100 +17,152 @@ type Message struct {
 	Content string `json:"content"`
 }
 
-type Request struct {
-	Model    string    `json:"model"`
-	Messages []Message `json:"messages"`
-	Stream   bool      `json:"stream"`
+// 檢查預算並記錄用量
+type Meter interface {
+	Check(ctx context.Context) error
+	Record(ctx context.Context, call Call)
 }
 
-type Response struct {
-	Choices []struct {
-		Delta struct {
-			Content string `json:"content"`
-		} `json:"delta"`
-	} `json:"choices"`
+// 一次完成的呼叫
+type Call struct {
+	Provider string
+	Model    string
+	Purpose  string
+	Usage    Usage
 }
 
-var ApiKey string
+type (
+	purposeKey struct{}
+	apiKeyKey  struct{}
+	meterKey   struct{}
+)
 
-func AskWithSmallModel(msgList []Message) (string, error) {
-	return askWithChatGPT("gpt-4o-mini", msgList)
+// 標註呼叫用途，記錄用量時使用
+func WithPurpose(ctx context.Context, purpose string) context.Context {
+	return context.WithValue(ctx, purposeKey{}, purpose)
 }
 
-func AskWithLargeModel(msgList []Message) (string, error) {
-	if ApiKey == "" || len(ApiKey) < 20 {
-		return "", fmt.Errorf("API key is not set")
-	}
-	return askWithChatGPT("gpt-4o", msgList)
+// 指定呼叫使用的 API key，隨 context 傳遞以免多個工作同時修改
+func WithAPIKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, apiKeyKey{}, key)
+}
+
+// 指定檢查預算與記錄用量的 Meter，未指定時不記錄
+func WithMeter(ctx context.Context, meter Meter) context.Context {
+	return context.WithValue(ctx, meterKey{}, meter)
+}
+
+// 逾時由呼叫端的 context 控制
+var client = &http.Client{}
+
+func AskWithSmallModel(ctx context.Context, msgList []Message) (string, error) {
+	return ask(ctx, config.Get().LLM.SmallModel, msgList, nil)
+}
+
+// 與 AskWithSmallModel 相同，每收到一段內容就呼叫 onDelta
+func StreamWithSmallModel(ctx context.Context, msgList []Message, onDelta func(string)) (string, error) {
+	return ask(ctx, config.Get().LLM.SmallModel, msgList, onDelta)
 }
 
-func askWithChatGPT(model string, msgList []Message) (string, error) {
-	body, err := json.Marshal(Request{
-		Model:    model,
-		Messages: msgList,
-		Stream:   true,
-	})
+func AskWithLargeModel(ctx context.Context, msgList []Message) (string, error) {
+	return ask(ctx, config.Get().LLM.LargeModel, msgList, nil)
+}
+
+func ask(ctx context.Context, model string, msgList []Message, onDelta func(string)) (string, error) {
+	cfg := config.Get().LLM
+	key, _ := ctx.Value(apiKeyKey{}).(string)
+	provider, err := NewProvider(cfg, key)
 	if err != nil {
 		return "", err
 	}
+	meter, _ := ctx.Value(meterKey{}).(Meter)
+	if meter != nil {
+		if err := meter.Check(ctx); err != nil {
+			return "", err
+		}
+	}
 
-	req, err := http.NewRequest("POST", "https://api.openai.com/v1/chat/completions", strings.NewReader(string(body)))
+	// 失敗的請求也可能計費，重試時累加
+	var usage Usage
+	result, err := withRetry(ctx, cfg.Retries, func(onDelta func(string)) (string, error) {
+		text, u, err := provider.Chat(ctx, model, msgList, onDelta)
+		usage.PromptTokens += u.PromptTokens
+		usage.CompletionTokens += u.CompletionTokens
+		return text, err
+	}, onDelta)
+
+	if meter != nil && usage.PromptTokens+usage.CompletionTokens > 0 {
+		purpose, _ := ctx.Value(purposeKey{}).(string)
+		meter.Record(context.WithoutCancel(ctx), Call{
+			Provider: cfg.Provider,
+			Model:    model,
+			Purpose:  purpose,
+			Usage:    usage,
+		})
+	}
+	return result, err
+}
+
+// 送出 JSON 請求，非 200 時回傳分類後的 *Error
+func post(ctx context.Context, url string, header http.Header, payload any) (*http.Response, error) {
+	body, err := json.Marshal(payload)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	for key, values := range header {
+		req.Header[key] = values
+	}
 	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", "Bearer "+ApiKey)
 
-	client := &http.Client{}
 	res, err := client.Do(req)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
-	defer res.Body.Close()
-
 	if res.StatusCode != http.StatusOK {
+		defer res.Body.Close()
 		bodyBytes, _ := io.ReadAll(res.Body)
-		return "", fmt.Errorf("API Error (Status %d): %s", res.StatusCode, string(bodyBytes))
+		return nil, newError(res, bodyBytes)
 	}
+	return res, nil
+}
 
+// 逐行讀取回應，SSE 只取 data: 後的內容，parse 回傳該行的文字片段
+func readStream(body io.Reader, sse bool, parse func(data []byte) string, onDelta func(string)) (string, error) {
 	var result strings.Builder
-	reader := bufio.NewReader(res.Body)
+	reader := bufio.NewReader(body)
 
 	for {
 		line, err := reader.ReadBytes('\n')
-		if err == io.EOF {
-			break
-		}
-		if err != nil {
+		if err != nil && err != io.EOF {
 			return "", err
 		}
 
-		lineStr := string(line)
-		if strings.TrimSpace(lineStr) == "" {
-			continue
-		}
-
-		if !strings.HasPrefix(lineStr, "data: ") {
-			continue
-		}
-		jsonData := strings.TrimPrefix(strings.TrimSpace(lineStr), "data: ")
-
-		if jsonData == "[DONE]" {
-			continue
-		}
-
-		var stream Response
-		if err := json.Unmarshal([]byte(jsonData), &stream); err != nil {
-			continue
+		data := bytes.TrimSpace(line)
+		if sse {
+			var ok bool
+			if data, ok = bytes.CutPrefix(data, []byte("data:")); !ok {
+				data = nil
+			}
+			data = bytes.TrimSpace(data)
 		}
 
-		if len(stream.Choices) > 0 {
-			content := stream.Choices[0].Delta.Content
-			if content != "" {
+		if len(data) > 0 && string(data) != "[DONE]" {
+			if content := parse(data); content != "" {
 				result.WriteString(content)
+				if onDelta != nil {
+					onDelta(content)
+				}
 			}
 		}
+
+		if err == io.EOF {
+			break
+		}
 	}
 
 	return result.String(), nil
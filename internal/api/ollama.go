@@ -0,0 +1,60 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+)
+
+// 本機 Ollama，串流回應為逐行 JSON
+type Ollama struct {
+	BaseURL string
+}
+
+type ollamaRequest struct {
+	Model    string    `json:"model"`
+	Messages []Message `json:"messages"`
+	Stream   bool      `json:"stream"`
+}
+
+type ollamaResponse struct {
+	Message Message `json:"message"`
+	Error   string  `json:"error"`
+	// 最後一行附上的 token 數
+	PromptEvalCount int `json:"prompt_eval_count"`
+	EvalCount       int `json:"eval_count"`
+}
+
+func (p *Ollama) Chat(ctx context.Context, model string, msgList []Message, onDelta func(string)) (string, Usage, error) {
+	res, err := post(ctx, p.BaseURL+"/api/chat", nil, ollamaRequest{
+		Model:    model,
+		Messages: msgList,
+		Stream:   true,
+	})
+	if err != nil {
+		return "", Usage{}, err
+	}
+	defer res.Body.Close()
+
+	var usage Usage
+	var streamErr error
+	result, err := readStream(res.Body, false, func(data []byte) string {
+		var stream ollamaResponse
+		if err := json.Unmarshal(data, &stream); err != nil {
+			return ""
+		}
+		if stream.Error != "" {
+			streamErr = streamError("", stream.Error)
+		}
+		if stream.EvalCount > 0 {
+			usage = Usage{PromptTokens: stream.PromptEvalCount, CompletionTokens: stream.EvalCount}
+		}
+		return stream.Message.Content
+	}, onDelta)
+	if err != nil {
+		return "", usage, err
+	}
+	if streamErr != nil {
+		return "", usage, streamErr
+	}
+	return result, usage, nil
+}
@@ -0,0 +1,95 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+)
+
+// OpenAI Chat Completions，也適用相容此格式的服務
+type OpenAI struct {
+	BaseURL string
+	Key     string
+	// 要求串流附上 token 用量；部分相容服務會拒絕不認得的 stream_options
+	StreamUsage bool
+}
+
+type openAIRequest struct {
+	Model         string               `json:"model"`
+	Messages      []Message            `json:"messages"`
+	Stream        bool                 `json:"stream"`
+	StreamOptions *openAIStreamOptions `json:"stream_options,omitempty"`
+}
+
+type openAIStreamOptions struct {
+	// 最後一段回應附上 token 用量
+	IncludeUsage bool `json:"include_usage"`
+}
+
+type openAIResponse struct {
+	Error *struct {
+		Type string `json:"type"`
+		// 各家服務可能回傳字串或數字
+		Code    json.RawMessage `json:"code"`
+		Message string          `json:"message"`
+	} `json:"error"`
+	Usage *struct {
+		PromptTokens     int `json:"prompt_tokens"`
+		CompletionTokens int `json:"completion_tokens"`
+	} `json:"usage"`
+	Choices []struct {
+		Delta struct {
+			Content string `json:"content"`
+		} `json:"delta"`
+	} `json:"choices"`
+}
+
+func (p *OpenAI) Chat(ctx context.Context, model string, msgList []Message, onDelta func(string)) (string, Usage, error) {
+	header := http.Header{}
+	if p.Key != "" {
+		header.Set("Authorization", "Bearer "+p.Key)
+	}
+
+	request := openAIRequest{
+		Model:    model,
+		Messages: msgList,
+		Stream:   true,
+	}
+	if p.StreamUsage {
+		request.StreamOptions = &openAIStreamOptions{IncludeUsage: true}
+	}
+
+	res, err := post(ctx, p.BaseURL+"/chat/completions", header, request)
+	if err != nil {
+		return "", Usage{}, err
+	}
+	defer res.Body.Close()
+
+	var usage Usage
+	var streamErr error
+	result, err := readStream(res.Body, true, func(data []byte) string {
+		var stream openAIResponse
+		if err := json.Unmarshal(data, &stream); err != nil {
+			return ""
+		}
+		if stream.Error != nil {
+			code := strings.Trim(string(stream.Error.Code), `"`)
+			streamErr = streamError(stream.Error.Type+" "+code, stream.Error.Message)
+		}
+		if stream.Usage != nil {
+			usage = Usage{PromptTokens: stream.Usage.PromptTokens, CompletionTokens: stream.Usage.CompletionTokens}
+		}
+		if len(stream.Choices) == 0 {
+			return ""
+		}
+		return stream.Choices[0].Delta.Content
+	}, onDelta)
+	if err != nil {
+		return "", usage, err
+	}
+	if streamErr != nil {
+		return "", usage, streamErr
+	}
+	return result, usage, nil
+}
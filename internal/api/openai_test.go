@@ -0,0 +1,75 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"rss-reader/internal/config"
+)
+
+func TestOpenAIStreamOptions(t *testing.T) {
+	tests := []struct {
+		provider string
+		want     bool
+	}{
+		{ProviderOpenAI, true},
+		{ProviderCompatible, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.provider, func(t *testing.T) {
+			var body map[string]any
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				data, _ := io.ReadAll(r.Body)
+				if err := json.Unmarshal(data, &body); err != nil {
+					t.Error(err)
+				}
+				io.WriteString(w, "data: {\"choices\":[{\"delta\":{\"content\":\"ok\"}}]}\n\ndata: [DONE]\n\n")
+			}))
+			defer server.Close()
+
+			provider, err := NewProvider(config.LLM{Provider: tt.provider, BaseURL: server.URL}, strings.Repeat("k", 40))
+			if err != nil {
+				t.Fatal(err)
+			}
+			result, _, err := provider.Chat(context.Background(), "model", []Message{{Role: "user", Content: "hi"}}, nil)
+			if err != nil || result != "ok" {
+				t.Fatalf("Chat = %q, %v", result, err)
+			}
+			if _, ok := body["stream_options"]; ok != tt.want {
+				t.Errorf("stream_options sent = %v, want %v", ok, tt.want)
+			}
+		})
+	}
+}
+
+func TestOpenAIStreamError(t *testing.T) {
+	tests := []struct {
+		name  string
+		event string
+		want  error
+	}{
+		{"string code", `{"error":{"type":"","code":"rate_limit_exceeded","message":"slow down"}}`, ErrRateLimit},
+		{"numeric code", `{"error":{"code":500,"message":"model crashed"}}`, ErrServer},
+		{"null code", `{"error":{"code":null,"message":"maximum context length exceeded"}}`, ErrContextLength},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				io.WriteString(w, "data: "+tt.event+"\n\ndata: [DONE]\n\n")
+			}))
+			defer server.Close()
+
+			provider := &OpenAI{BaseURL: server.URL}
+			_, _, err := provider.Chat(context.Background(), "model", []Message{{Role: "user", Content: "hi"}}, nil)
+			if !errors.Is(err, tt.want) {
+				t.Errorf("err = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
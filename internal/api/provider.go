@@ -0,0 +1,69 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"rss-reader/internal/config"
+)
+
+const (
+	ProviderOpenAI     = "openai"
+	ProviderCompatible = "openai-compatible"
+	ProviderAnthropic  = "anthropic"
+	ProviderOllama     = "ollama"
+)
+
+// 串流呼叫聊天模型，onDelta 可為 nil；服務沒有回報用量時 Usage 為零值
+type Provider interface {
+	Chat(ctx context.Context, model string, msgList []Message, onDelta func(string)) (string, Usage, error)
+}
+
+type Usage struct {
+	PromptTokens     int
+	CompletionTokens int
+}
+
+var errNoKey = &Error{Kind: ErrAuth, Message: "API key is not set"}
+
+// 依設定建立 provider，base_url 為空時使用各家的預設位址
+func NewProvider(cfg config.LLM, key string) (Provider, error) {
+	baseURL := strings.TrimRight(cfg.BaseURL, "/")
+
+	switch cfg.Provider {
+	case ProviderOpenAI, "":
+		if len(key) < 20 {
+			return nil, errNoKey
+		}
+		if baseURL == "" {
+			baseURL = "https://api.openai.com/v1"
+		}
+		return &OpenAI{BaseURL: baseURL, Key: key, StreamUsage: true}, nil
+
+	case ProviderCompatible:
+		if baseURL == "" {
+			return nil, errors.New("llm.base_url is required for openai-compatible")
+		}
+		// 自架服務通常不需要 key
+		return &OpenAI{BaseURL: baseURL, Key: key}, nil
+
+	case ProviderAnthropic:
+		if key == "" {
+			return nil, errNoKey
+		}
+		if baseURL == "" {
+			baseURL = "https://api.anthropic.com"
+		}
+		return &Anthropic{BaseURL: baseURL, Key: key}, nil
+
+	case ProviderOllama:
+		if baseURL == "" {
+			baseURL = "http://localhost:11434"
+		}
+		return &Ollama{BaseURL: baseURL}, nil
+	}
+
+	return nil, fmt.Errorf("unknown llm provider %q", cfg.Provider)
+}
@@ -0,0 +1,74 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand/v2"
+	"net"
+	"syscall"
+	"time"
+)
+
+const (
+	baseBackoff = time.Second
+	maxBackoff  = 30 * time.Second
+	// Retry-After 超過此時間時直接回傳錯誤
+	maxRetryAfter = 2 * time.Minute
+)
+
+// 限流、伺服器錯誤與暫時性的連線錯誤可重試；網址錯誤、無法解析的主機等不重試
+func retryable(err error) bool {
+	if errors.Is(err, ErrRateLimit) || errors.Is(err, ErrServer) {
+		return true
+	}
+	if errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, syscall.ECONNABORTED) || errors.Is(err, syscall.EPIPE) ||
+		errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, io.EOF) {
+		return true
+	}
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		return dnsErr.IsTimeout || dnsErr.IsTemporary
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+// 等待時間優先採用 Retry-After，否則為指數退避加上 full jitter
+func retryDelay(err error, attempt int) time.Duration {
+	var apiErr *Error
+	if errors.As(err, &apiErr) && apiErr.RetryAfter > 0 {
+		return apiErr.RetryAfter
+	}
+	backoff := min(baseBackoff<<attempt, maxBackoff)
+	return rand.N(backoff) + time.Millisecond
+}
+
+// 失敗時依 retries 次數重試；已輸出部分內容給呼叫端後不重試，避免重複
+func withRetry(ctx context.Context, retries int, call func(onDelta func(string)) (string, error), onDelta func(string)) (string, error) {
+	for attempt := 0; ; attempt++ {
+		streamed := false
+		var forward func(string)
+		if onDelta != nil {
+			forward = func(delta string) {
+				streamed = true
+				onDelta(delta)
+			}
+		}
+		result, err := call(forward)
+		if err == nil || ctx.Err() != nil || streamed || attempt >= retries || !retryable(err) {
+			return result, err
+		}
+
+		delay := retryDelay(err, attempt)
+		if delay > maxRetryAfter {
+			return "", err
+		}
+		select {
+		case <-ctx.Done():
+			return "", ctx.Err()
+		case <-time.After(delay):
+		}
+	}
+}
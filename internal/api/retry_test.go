@@ -0,0 +1,144 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/url"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestWithRetry(t *testing.T) {
+	// RetryAfter 很短，測試不需等待退避時間
+	rateLimited := &Error{Kind: ErrRateLimit, RetryAfter: time.Millisecond}
+	serverError := &Error{Kind: ErrServer, RetryAfter: time.Millisecond}
+	authError := &Error{Kind: ErrAuth}
+
+	tests := []struct {
+		name    string
+		retries int
+		results []error
+		stream  bool
+		calls   int
+		err     error
+	}{
+		{"success", 2, []error{nil}, false, 1, nil},
+		{"retry then success", 2, []error{rateLimited, serverError, nil}, false, 3, nil},
+		{"retries exhausted", 2, []error{serverError, serverError, serverError, nil}, false, 3, ErrServer},
+		{"no retries", 0, []error{serverError, nil}, false, 1, ErrServer},
+		{"not retryable", 3, []error{authError, nil}, false, 1, ErrAuth},
+		{"streamed output not retried", 3, []error{serverError, nil}, true, 1, ErrServer},
+		{"retry after too long", 3, []error{&Error{Kind: ErrRateLimit, RetryAfter: time.Hour}, nil}, false, 1, ErrRateLimit},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			var deltas []string
+			result, err := withRetry(context.Background(), tt.retries, func(onDelta func(string)) (string, error) {
+				err := tt.results[calls]
+				calls++
+				if tt.stream {
+					onDelta("partial")
+				}
+				if err != nil {
+					return "", err
+				}
+				return "done", nil
+			}, func(delta string) {
+				deltas = append(deltas, delta)
+			})
+
+			if calls != tt.calls {
+				t.Errorf("calls = %d, want %d", calls, tt.calls)
+			}
+			if tt.err == nil {
+				if err != nil || result != "done" {
+					t.Errorf("got %q, %v, want done", result, err)
+				}
+			} else if !errors.Is(err, tt.err) {
+				t.Errorf("err = %v, want %v", err, tt.err)
+			}
+			if tt.stream && len(deltas) != calls {
+				t.Errorf("deltas = %v, want one per call", deltas)
+			}
+		})
+	}
+}
+
+func TestWithRetryCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	calls := 0
+	_, err := withRetry(ctx, 5, func(onDelta func(string)) (string, error) {
+		calls++
+		cancel()
+		return "", &Error{Kind: ErrServer, RetryAfter: time.Millisecond}
+	}, nil)
+
+	if calls != 1 {
+		t.Errorf("calls = %d, want 1", calls)
+	}
+	if !errors.Is(err, ErrServer) {
+		t.Errorf("err = %v, want %v", err, ErrServer)
+	}
+}
+
+func TestWithRetryWithoutOutput(t *testing.T) {
+	// 呼叫端不接收串流輸出時，失敗仍可重試
+	calls := 0
+	result, err := withRetry(context.Background(), 2, func(onDelta func(string)) (string, error) {
+		calls++
+		if onDelta != nil {
+			t.Error("onDelta should be nil when the caller does not stream")
+		}
+		if calls == 1 {
+			return "", &Error{Kind: ErrServer, RetryAfter: time.Millisecond}
+		}
+		return "done", nil
+	}, nil)
+
+	if calls != 2 || err != nil || result != "done" {
+		t.Errorf("got %q, %v after %d calls, want done after 2", result, err, calls)
+	}
+}
+
+func TestRetryable(t *testing.T) {
+	post := func(err error) error {
+		return &url.Error{Op: "Post", URL: "https://api.example.com/v1/chat", Err: err}
+	}
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"rate limit", &Error{Kind: ErrRateLimit}, true},
+		{"auth", &Error{Kind: ErrAuth}, false},
+		{"connection reset", post(&net.OpError{Op: "read", Err: syscall.ECONNRESET}), true},
+		{"connection refused", post(&net.OpError{Op: "dial", Err: syscall.ECONNREFUSED}), true},
+		{"unexpected eof", post(io.ErrUnexpectedEOF), true},
+		{"timeout", post(&net.DNSError{Err: "i/o timeout", IsTimeout: true}), true},
+		{"unknown host", post(&net.DNSError{Err: "no such host", Name: "api.example.com", IsNotFound: true}), false},
+		{"unsupported scheme", post(errors.New(`unsupported protocol scheme "htp"`)), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := retryable(tt.err); got != tt.want {
+				t.Errorf("retryable(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRetryDelay(t *testing.T) {
+	if got := retryDelay(&Error{Kind: ErrRateLimit, RetryAfter: 7 * time.Second}, 3); got != 7*time.Second {
+		t.Errorf("retryDelay with Retry-After = %v, want 7s", got)
+	}
+	for attempt := 0; attempt < 10; attempt++ {
+		limit := min(baseBackoff<<attempt, maxBackoff) + time.Millisecond
+		if got := retryDelay(errors.New("network"), attempt); got <= 0 || got > limit {
+			t.Errorf("retryDelay attempt %d = %v, want in (0, %v]", attempt, got, limit)
+		}
+	}
+}
@@ -1,15 +1,17 @@
 package app
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"log"
 	"os/exec"
 	"runtime"
-	"sort"
 	"strings"
+	"sync"
 	"time"
 
-	"rss-reader/internal/api"
+	"rss-reader/internal/config"
 	"rss-reader/internal/database"
 	"rss-reader/internal/model"
 	"rss-reader/internal/util"
@@ -22,8 +24,13 @@ type App struct {
 	app              *tview.Application
 	collector        *util.Collector
 	extractor        *util.Extractor
+	updater          *util.Updater
+	summarizer       *util.Summarizer
+	archiver         *util.Archiver
+	translator       *util.Translator
 	database         *database.SQLite
 	list             *tview.List
+	leftView         *tview.Flex
 	llmView          *tview.TextView
 	preview          *tview.TextView
 	input            *tview.InputField
@@ -33,6 +40,17 @@ type App struct {
 	ticker           *time.Ticker
 	stopChan         chan bool
 	autoRefresh      bool
+	ctx              context.Context
+	cancel           context.CancelFunc
+	task             taskSlot
+	refreshTask      taskSlot
+	lock             *util.Lock
+	readOnly         bool
+	pendingRule      *util.Rule
+	filter           listFilter
+	history          []database.Summary
+	historyIndex     int
+	historyDiff      bool
 }
 
 func New() *App {
@@ -41,15 +59,37 @@ func New() *App {
 		log.Fatalf("Failed to init SQLite: %v", err)
 	}
 
+	ctx, cancel := context.WithCancel(context.Background())
+
+	collector := util.NewCollector(db)
+	extractor := util.NewExtractor()
+
 	app := &App{
+		ctx:         ctx,
+		cancel:      cancel,
 		app:         tview.NewApplication(),
-		collector:   util.NewCollector(db),
-		extractor:   util.NewExtractor(),
+		collector:   collector,
+		extractor:   extractor,
+		updater:     util.NewUpdater(db, collector, extractor),
+		summarizer:  util.NewSummarizer(db),
+		archiver:    util.NewArchiver(db),
+		translator:  util.NewTranslator(db),
 		database:    db,
-		ticker:      time.NewTicker(5 * time.Minute),
+		ticker:      time.NewTicker(config.Get().Refresh.Interval),
 		stopChan:    make(chan bool),
 		autoRefresh: true,
 	}
+	// daemon 執行中時改為唯讀，僅定期從資料庫重新載入
+	lock, err := util.AcquireLock(util.LockPath(db.Path()))
+	var locked *util.LockedError
+	if errors.As(err, &locked) {
+		app.readOnly = true
+		db.SetReadOnly()
+	} else if err != nil {
+		log.Printf("Failed to acquire lock: %v", err)
+	}
+	app.lock = lock
+
 	app.frame()
 	app.refresh()
 	return app
@@ -79,15 +119,16 @@ func (a *App) frame() {
 	a.llmView.SetBorder(true).
 		SetTitle("Summary").
 		SetTitleAlign(tview.AlignLeft)
-	summary, _ := a.database.GetKey("summary")
+	a.llmView.SetInputCapture(a.historyInput)
+	summary, _ := a.database.GetKey(a.ctx, "summary")
 	if summary != "" {
 		a.llmView.SetText(summary).ScrollToBeginning()
 	}
 
-	leftView := tview.NewFlex().
+	a.leftView = tview.NewFlex().
 		SetDirection(tview.FlexRow).
 		AddItem(a.llmView, 0, 1, true).
-		AddItem(a.list, 18, 0, true)
+		AddItem(a.list, config.Get().UI.ListHeight, 0, true)
 
 	a.preview = tview.NewTextView().
 		SetDynamicColors(true).
@@ -110,7 +151,7 @@ func (a *App) frame() {
 		AddItem(a.input, 3, 0, false)
 
 	mainFlex := tview.NewFlex().
-		AddItem(leftView, 0, 1, true).
+		AddItem(a.leftView, 0, 1, true).
 		AddItem(rightView, 0, 1, false)
 
 	rootFlex := tview.NewFlex().
@@ -128,7 +169,8 @@ func (a *App) refresh() {
 		for {
 			select {
 			case <-a.ticker.C:
-				if a.autoRefresh {
+				// 上一次更新尚未完成時略過
+				if a.autoRefresh && !a.refreshTask.busy() {
 					a.app.QueueUpdateDraw(func() {
 						a.updateStatus("Refreshing...")
 					})
@@ -158,6 +200,18 @@ func (a *App) listener() {
 		case tcell.KeyCtrlR:
 			a.getList(true)
 			return nil
+		case tcell.KeyEscape:
+			// 有進行中的更新或概要時中止，否則交由元件處理
+			if a.cancelTask() {
+				a.updateStatus("Cancelled.")
+				return nil
+			}
+		case tcell.KeyCtrlS:
+			a.summarizeArticle()
+			return nil
+		case tcell.KeyCtrlE:
+			a.reextract()
+			return nil
 		case tcell.KeyCtrlO:
 			index := a.list.GetCurrentItem()
 			if index >= 0 && index < len(a.filteredArticles) {
@@ -193,7 +247,10 @@ func (a *App) command(command string) {
 			return
 		}
 		url := parts[1]
-		a.collector.Add(url)
+		if !a.writable() {
+			return
+		}
+		a.collector.Add(a.ctx, url)
 		a.showCommand(fmt.Sprintf("Add RSS: %s", cmd))
 		a.showFeedList()
 
@@ -203,7 +260,10 @@ func (a *App) command(command string) {
 			return
 		}
 		url := parts[1]
-		a.collector.Remove(url)
+		if !a.writable() {
+			return
+		}
+		a.collector.Remove(a.ctx, url)
 		a.showCommand(fmt.Sprintf("Remove RSS: %s", url))
 		a.showFeedList()
 
@@ -213,7 +273,10 @@ func (a *App) command(command string) {
 			return
 		}
 		key := parts[1]
-		if err := a.database.SetKey("apikey", key); err != nil {
+		if !a.writable() {
+			return
+		}
+		if err := a.database.SetKey(a.ctx, "apikey", key); err != nil {
 			a.showCommand(fmt.Sprintf("Failed to set API key: %v", err))
 			return
 		}
@@ -222,13 +285,129 @@ func (a *App) command(command string) {
 	case "config":
 		a.showFeedList()
 
+	case "history":
+		a.history = nil
+		a.historyDiff = false
+		a.showHistory(0)
+		a.app.SetFocus(a.llmView)
+
+	case "diff":
+		a.historyDiff = !a.historyDiff
+		a.showHistory(a.historyIndex)
+		a.app.SetFocus(a.llmView)
+
+	case "usage":
+		report, err := util.NewUsageReport(a.ctx, a.database)
+		if err != nil {
+			a.showCommand(fmt.Sprintf("Failed to get usage: %v", err))
+			return
+		}
+		a.showCommand(tview.Escape(report.String()))
+
+	case "reload":
+		a.reload()
+
+	case "rule", "rules":
+		a.ruleCommand(parts, command)
+
+	case "prompt", "prompts":
+		a.promptCommand(parts)
+
+	case "summarize":
+		a.summarizeArticle()
+
+	case "translate":
+		a.translate(parts)
+
+	case "sort":
+		a.sortCommand(parts)
+
+	case "filter":
+		a.filterCommand(parts)
+
+	case "extract":
+		a.reextract()
+
+	case "archive":
+		a.archive(false)
+
+	case "open-archive":
+		a.archive(true)
+
+	case "cancel":
+		if a.cancelTask() {
+			a.updateStatus("Cancelled.")
+		} else {
+			a.showCommand("No running task.")
+		}
+
 	default:
 		a.showCommand(fmt.Sprintf("未知指令: %s", cmd))
 	}
 }
 
+// 重新讀取設定檔並套用至更新間隔與介面
+func (a *App) reload() {
+	if err := config.Reload(); err != nil {
+		a.showCommand(fmt.Sprintf("Failed to reload config: %v", err))
+		return
+	}
+
+	if err := a.extractor.ReloadRules(); err != nil {
+		a.showCommand(fmt.Sprintf("Failed to reload rules: %v", err))
+		return
+	}
+
+	cfg := config.Get()
+	a.ticker.Reset(cfg.Refresh.Interval)
+	a.leftView.ResizeItem(a.list, cfg.UI.ListHeight, 0)
+	a.updateStatus("Config reloaded.")
+	a.showCommand(fmt.Sprintf("Config reloaded: %s", config.File()))
+}
+
+// 保存目前文章的離線快照，open 為 true 時優先開啟既有快照
+func (a *App) archive(open bool) {
+	index := a.list.GetCurrentItem()
+	if index < 0 || index >= len(a.filteredArticles) {
+		a.showCommand("No article selected.")
+		return
+	}
+	url := a.filteredArticles[index].URL
+
+	if open {
+		if p, err := a.archiver.Path(a.ctx, url); err == nil {
+			go a.openBrowser(p)
+			a.updateStatus("Opened archive.")
+			return
+		}
+	}
+
+	if !a.writable() {
+		return
+	}
+	ctx, done := a.startTask()
+	a.updateStatus("Archiving...")
+	go func() {
+		defer done()
+
+		p, err := a.archiver.Archive(ctx, url)
+		a.app.QueueUpdateDraw(func() {
+			if err != nil {
+				if ctx.Err() == nil {
+					a.updateStatus(fmt.Sprintf("Failed to archive: %v", err))
+				}
+				return
+			}
+			a.updateStatus(fmt.Sprintf("Archived: %s", p))
+		})
+		if err == nil && open {
+			a.openBrowser(p)
+		}
+	}()
+}
+
 func (a *App) showFeedList() {
-	feeds, err := a.collector.List()
+	feeds, err := a.collector.List(a.ctx)
 	if err != nil {
 		a.showCommand(fmt.Sprintf("Failed to list RSS feed: %v", err))
 		return
@@ -239,18 +418,28 @@ func (a *App) showFeedList() {
 		return
 	}
 
-	key, _ := a.database.GetKey("apikey")
+	key, _ := a.database.GetKey(a.ctx, "apikey")
 	if key == "" {
 		key = "Not set"
 	}
 
-	result := fmt.Sprintf("API Key: %s\n\n%s\n", key, "RSS feed list:")
+	llm := config.Get().LLM
+	result := fmt.Sprintf("API Key: %s\nProvider: %s %s\n\n%s\n", key, llm.Provider, llm.BaseURL, "RSS feed list:")
 	for i, feed := range feeds {
 		result += fmt.Sprintf("%d. %s\n", i+1, feed)
 	}
 	a.showCommand(result)
 }
 
+// daemon 執行中時不允許修改資料庫的指令
+func (a *App) writable() bool {
+	if a.readOnly {
+		a.showCommand("Read-only while the daemon is running.")
+		return false
+	}
+	return true
+}
+
 func (a *App) showCommand(message string) {
 	a.preview.SetText(message).ScrollToBeginning()
 }
@@ -265,7 +454,69 @@ func (a *App) stopRefresh() {
 	}
 }
 
+// 可中止的工作，同一個 slot 同時只有一個工作
+type taskSlot struct {
+	mu     sync.Mutex
+	cancel context.CancelFunc
+	id     int
+}
+
+// 開始新的工作並取消同一個 slot 中尚在進行的工作；回傳的 done 於工作結束時呼叫
+func (s *taskSlot) start(parent context.Context) (context.Context, func()) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.cancel != nil {
+		s.cancel()
+	}
+	ctx, cancel := context.WithCancel(parent)
+	s.cancel = cancel
+	s.id++
+	id := s.id
+
+	done := func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		cancel()
+		if s.id == id {
+			s.cancel = nil
+		}
+	}
+	return ctx, done
+}
+
+func (s *taskSlot) stop() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.cancel == nil {
+		return false
+	}
+	s.cancel()
+	s.cancel = nil
+	return true
+}
+
+func (s *taskSlot) busy() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.cancel != nil
+}
+
+// 使用者操作（概要、翻譯、封存等）的工作；列表更新使用另一個 slot，不會互相取消
+func (a *App) startTask() (context.Context, func()) {
+	return a.task.start(a.ctx)
+}
+
+// 優先中止使用者操作，沒有時中止列表更新
+func (a *App) cancelTask() bool {
+	return a.task.stop() || a.refreshTask.stop()
+}
+
 func (a *App) getList(isRefresh bool) {
+	ctx, done := a.refreshTask.start(a.ctx)
+
 	if isRefresh {
 		a.updateStatus("Checking...")
 	} else {
@@ -273,60 +524,49 @@ func (a *App) getList(isRefresh bool) {
 	}
 
 	go func() {
+		if a.readOnly {
+			a.reloadList(ctx)
+			done()
+			return
+		}
+
 		// 1. 如果列表為空，先從資料庫載入
 		if len(a.articles) < 1 {
-			storedArticles, err := a.database.Get(72)
+			storedArticles, err := a.database.Get(ctx, config.Hours(config.Get().Retention.Feed))
 			if err == nil && len(storedArticles) > 0 {
 				a.app.QueueUpdateDraw(func() {
 					a.articles = storedArticles
-					a.filteredArticles = a.articles
-					a.updateList()
+					a.applyFilter()
 					a.updateStatus(fmt.Sprintf("Get %d news from Database", len(a.articles)))
 				})
 			}
 		}
 
-		// 2. 從 RSS 獲取新文章
-		newArticles, err := a.collector.GetNews()
+		// 2. 從 RSS 獲取新文章，並比對資料庫中沒有的文章
+		finalArticles, newCount, err := a.updater.Check(ctx)
+		if ctx.Err() != nil {
+			done()
+			return
+		}
 		if err != nil {
+			done()
 			a.app.QueueUpdateDraw(func() {
 				a.updateStatus(fmt.Sprintf("Failed to get news list: %v", err))
 			})
 			return
 		}
 
-		// 3. 檢查並插入資料庫中沒有的文章
-		newCount := 0
-		finalArticles := make([]model.News, 0)
-
-		for _, article := range newArticles {
-			stored, err := a.database.GetFromURL(article.URL)
-			if err != nil {
-				// 資料庫沒有這篇文章，計入新文章
-				newCount++
-				finalArticles = append(finalArticles, article)
-			} else {
-				// 使用資料庫中的發布時間
-				article.PublishedAt = stored.PublishedAt
-				finalArticles = append(finalArticles, article)
-			}
-		}
-
-		// 按發布時間排序 (新到舊)
-		sort.Slice(finalArticles, func(i, j int) bool {
-			return finalArticles[i].PublishedAt.After(finalArticles[j].PublishedAt)
-		})
-
-		// 4. 非同步載入新文章的完整內容
+		// 3. 非同步載入新文章的完整內容
 		if newCount > 0 {
-			go a.loadContent(finalArticles)
+			go a.loadContent(ctx, done, finalArticles)
+		} else {
+			done()
 		}
 
-		// 5. 更新 UI
+		// 4. 更新 UI
 		a.app.QueueUpdateDraw(func() {
 			a.articles = finalArticles
-			a.filteredArticles = finalArticles
-			a.updateList()
+			a.applyFilter()
 			if newCount > 0 {
 				a.updateStatus(fmt.Sprintf("Found %d new articles, getting full content...", newCount))
 			} else {
@@ -336,124 +576,84 @@ func (a *App) getList(isRefresh bool) {
 	}()
 }
 
-func (a *App) loadContent(news []model.News) {
-	for i, article := range news {
-		stored, err := a.database.GetFromURL(article.URL)
-		if err == nil && stored.FullContent != nil {
-			continue
-		}
-
-		extracted, err := a.extractor.Get(article.URL)
-		if err != nil {
-			log.Printf("Failed to get content %s: %v", article.URL, err)
-
-			if err := a.database.Insert(article, nil); err != nil {
-				log.Printf("Failed to store news %s: %v", article.URL, err)
-			}
-			continue
-		}
-
-		if err := a.database.Insert(article, extracted); err != nil {
-			log.Printf("Failed to store news %s: %v", article.URL, err)
-		}
-
-		progress := float64(i+1) / float64(len(news)) * 100
+// 由 daemon 負責抓取時，只從資料庫讀取文章與概要
+func (a *App) reloadList(ctx context.Context) {
+	storedArticles, err := a.database.Get(ctx, config.Hours(config.Get().Retention.Feed))
+	if err != nil {
 		a.app.QueueUpdateDraw(func() {
-			a.updateStatus(fmt.Sprintf("Progress: %.1f%% (%d/%d)", progress, i+1, len(news)))
+			a.updateStatus(fmt.Sprintf("Failed to get news list: %v", err))
 		})
-
-		time.Sleep(500 * time.Millisecond)
+		return
 	}
+	summary, _ := a.database.GetKey(ctx, "summary")
 
 	a.app.QueueUpdateDraw(func() {
-		a.updateStatus("All news are up to date")
-
-		key, _ := a.database.GetKey("apikey")
-		if key != "" {
-			api.ApiKey = key
-		}
-		summary, _ := a.database.GetKey("summary")
-		systemPrompt := strings.TrimSpace(
-			fmt.Sprintf(`=== 系統資訊 ===
-當前時間：%s
-作業系統與執行環境：%s
-
-=== 指令說明 ===
-你是專業的新聞概要整理助手。請根據輸入的新聞內容（標題、日期、完整內容）提取重點，生成結構化的本日概要並分析趨勢。
-
-=== 輸出格式要求 ===
-使用繁體中文，按以下順序組織內容：
-
-## 重大要聞
-- 國際局勢、政府政策、重大社會事件
-- 保留詳細資訊，避免過度壓縮
-- 標註時間與影響程度
-
-## 科技與金融
-- 科技趨勢、市場動向、經濟指標
-- 重點關注創新技術與投資機會
-- 標註相關股市或產業影響
-
-## 生活資訊
-- 天氣預報、交通狀況、消費資訊
-- 健康醫療、教育文化相關消息
-
-## 趨勢分析
-- 對比前次概要，標註變化趨勢
-- 識別持續發展或新興話題
-- 預測可能後續發展
-
-=== 處理原則 ===
-1. 盡可能地保留舊有概要內容（包含24小時內、重大消息）
-2. 依新聞重要性與時效性排序
-3. 盡可能從相近新聞中補充細節
-4. 保留數據、時間、人名等關鍵細節
-5. 標註消息來源可信度
-6. 突出與前次概要的差異變化
-
-
-=== 前次概要 ===
-%s`,
-				time.Now().Format("2006年01月02日 15:04:05"),
-				runtime.GOOS+"/"+runtime.GOARCH,
-				summary,
-			),
-		)
-		messages := []api.Message{
-			{
-				Role:    "system",
-				Content: systemPrompt,
-			},
-		}
-
-		if summary == "" {
-			arr, _ := a.database.Get(24)
-			sort.Slice(arr, func(i, j int) bool {
-				return arr[i].PublishedAt.After(arr[j].PublishedAt)
-			})
-
-			for _, item := range arr {
-				messages = append(messages, api.Message{
-					Role:    "user",
-					Content: fmt.Sprintf("Title: %s\nSource: %s\nPublishedAt: %s\nContent: %s", item.Title, item.Source, item.PublishedAt.Local().Format("2006-01-02 15:04"), item.Content),
-				})
-			}
+		a.articles = storedArticles
+		a.applyFilter()
+		// 翻閱歷史時不覆蓋
+		if summary != "" && a.historyIndex == 0 && !a.historyDiff {
+			a.llmView.SetText(summary)
 		}
+		a.updateStatus(fmt.Sprintf("Get %d news from Database (daemon running)", len(a.articles)))
+	})
+}
 
-		for _, item := range news {
-			messages = append(messages, api.Message{
-				Role:    "user",
-				Content: fmt.Sprintf("Title: %s\nSource: %s\nPublishedAt: %s\nContent: %s", item.Title, item.Source, item.PublishedAt.Local().Format("2006-01-02 15:04"), item.Content),
-			})
-		}
+func (a *App) loadContent(ctx context.Context, done func(), news []model.News) {
+	defer done()
 
-		summary, err := api.AskWithSmallModel(messages)
-		if err != nil {
-			a.llmView.SetText(err.Error()).ScrollToBeginning()
+	err := a.updater.Load(ctx, news, func(current, total int) {
+		progress := float64(current) / float64(total) * 100
+		a.app.QueueUpdateDraw(func() {
+			a.updateStatus(fmt.Sprintf("Progress: %.1f%% (%d/%d)", progress, current, total))
+		})
+	})
+	if ctx.Err() != nil {
+		return
+	}
+	if err != nil {
+		a.app.QueueUpdateDraw(func() {
+			a.updateStatus(fmt.Sprintf("Failed to load articles: %v", err))
+		})
+		return
+	}
+	a.updateExtracted(ctx)
+
+	a.app.QueueUpdateDraw(a.resetHistory)
+	spin := a.startSpinner(ctx, "Generating summary... (Esc to cancel)")
+	var text strings.Builder
+	var last time.Time
+	summary, err := a.summarizer.Generate(ctx, news, func(current, total int) {
+		spin.set(fmt.Sprintf("Generating summary... batch %d/%d (Esc to cancel)", current+1, total))
+	}, func(delta string) {
+		// 概要逐段顯示，中止時保留已產生的部分
+		text.WriteString(delta)
+		if time.Since(last) < redrawInterval {
 			return
 		}
-		a.database.SetKey("summary", summary)
+		last = time.Now()
+		current := text.String()
+		a.app.QueueUpdateDraw(func() {
+			if ctx.Err() == nil {
+				a.llmView.SetText(current)
+			}
+		})
+	})
+	spin.stop()
+	if ctx.Err() != nil {
+		return
+	}
+	if err != nil {
+		// 保留前次概要，錯誤只顯示在狀態列
+		previous, _ := a.database.GetKey(ctx, "summary")
+		a.app.QueueUpdateDraw(func() {
+			a.llmView.SetText(previous).ScrollToBeginning()
+			a.updateStatus(llmStatus("generate summary", err))
+		})
+		return
+	}
+	a.app.QueueUpdateDraw(func() {
 		a.llmView.SetText(summary).ScrollToBeginning()
+		a.updateStatus("All news are up to date")
 	})
 }
 
@@ -461,61 +661,152 @@ func (a *App) updateList() {
 	a.list.Clear()
 
 	for _, e := range a.filteredArticles {
-		title := e.Title
-		timeStr := e.PublishedAt.Local().Format("01/02 15:04")
-		date := fmt.Sprintf("%s | %s", timeStr, e.Source)
-
+		title, date := a.itemText(e)
 		a.list.AddItem(title, date, 0, nil)
 	}
 }
 
+func (a *App) itemText(e model.News) (string, string) {
+	title := e.Title
+	switch e.Status {
+	case model.StatusPaywalled:
+		title = "[red]Paywall[-] " + title
+	case model.StatusConsent:
+		title = "[red]Consent[-] " + title
+	}
+	timeStr := e.PublishedAt.Local().Format("01/02 15:04")
+	date := fmt.Sprintf("%s | %s", timeStr, e.Source)
+	if minutes := e.Minutes(); minutes > 0 {
+		date += fmt.Sprintf(" | %d min", minutes)
+	}
+	return title, date
+}
+
+// 擷取完成後以資料庫中的狀態、閱讀時間與語言更新列表，保留目前的選取位置
+func (a *App) updateExtracted(ctx context.Context) {
+	stored, err := a.database.Get(ctx, config.Hours(config.Get().Retention.Feed))
+	if err != nil {
+		return
+	}
+	extracted := make(map[string]model.News, len(stored))
+	for _, e := range stored {
+		extracted[e.URL] = e
+	}
+	apply := func(e *model.News) {
+		if s, ok := extracted[e.URL]; ok {
+			e.Status = s.Status
+			e.ReadingTime = s.ReadingTime
+			e.Language = s.Language
+		}
+	}
+
+	a.app.QueueUpdateDraw(func() {
+		for i := range a.articles {
+			apply(&a.articles[i])
+		}
+		// 篩選或排序依賴擷取結果時需重新產生列表
+		if a.filter.active() || a.filter.sortBy == "reading" {
+			a.applyFilter()
+			return
+		}
+		for i := range a.filteredArticles {
+			apply(&a.filteredArticles[i])
+			if i < a.list.GetItemCount() {
+				title, date := a.itemText(a.filteredArticles[i])
+				a.list.SetItemText(i, title, date)
+			}
+		}
+	})
+}
+
 func (a *App) showPreview(news model.News) {
 	a.app.QueueUpdateDraw(func() {
 		a.preview.SetText("[yellow]Loading...[white]")
 	})
 
-	stored, err := a.database.GetFromURL(news.URL)
-	if err == nil && stored.FullContent != nil {
+	// 已擷取過的文章（包含擷取失敗）直接顯示，不重新抓取
+	stored, err := a.database.GetFromURL(a.ctx, news.URL)
+	if err == nil && stored.IsExtracted() {
+		extracted := stored.Extracted()
+		if !config.Get().UI.ArticleSummary {
+			extracted.Summary = ""
+		}
 		a.app.QueueUpdateDraw(func() {
-			author := ""
-			if stored.Author != nil {
-				author = *stored.Author
-			}
-
-			content := ""
-			if stored.FullContent != nil {
-				content = *stored.FullContent
-			}
-
-			wordCount := 0
-			if stored.WordCount != nil {
-				wordCount = *stored.WordCount
-			}
-
-			extracted := &model.NewsContent{
-				Title:     stored.Title,
-				Author:    author,
-				Content:   content,
-				WordCount: wordCount,
-			}
+			news.PublishedAt = stored.PublishedAt
 			a.showFull(news, extracted)
 		})
 		return
 	}
 
-	extracted, err := a.extractor.Get(news.URL)
-	if err != nil {
-		a.app.QueueUpdateDraw(func() {
-			a.showBasicPreview(news)
-		})
-		return
+	a.extractArticle(a.ctx, news)
+}
+
+// 擷取文章並寫入資料庫後顯示，中止或寫入失敗時回傳 false
+func (a *App) extractArticle(ctx context.Context, news model.News) bool {
+	extracted, _ := a.extractor.Extract(ctx, news)
+	if extracted == nil {
+		return false
 	}
 
-	go a.database.Insert(news, extracted)
+	util.ApplyPubDate(&news, extracted)
+	// 已擷取的內容即使中止仍寫入；daemon 執行中時由 daemon 擷取，不視為錯誤
+	err := a.database.Insert(a.ctx, news, extracted)
+	failed := err != nil && !errors.Is(err, database.ErrReadOnly)
+	if failed {
+		log.Printf("Failed to store article %s: %v", news.URL, err)
+	}
 
 	a.app.QueueUpdateDraw(func() {
 		a.showFull(news, extracted)
+		if failed {
+			a.updateStatus(fmt.Sprintf("Failed to store article: %v", err))
+		}
 	})
+	return !failed
+}
+
+// 從資料庫讀取文章，尚未擷取時先擷取並寫入；唯讀時只擷取不寫入
+func (a *App) storedArticle(ctx context.Context, news model.News) (*model.News, error) {
+	stored, err := a.database.GetFromURL(ctx, news.URL)
+	if err == nil && stored.IsExtracted() {
+		return stored, nil
+	}
+
+	extracted, err := a.extractor.Extract(ctx, news)
+	if extracted == nil {
+		return nil, err
+	}
+	util.ApplyPubDate(&news, extracted)
+	if a.readOnly {
+		news.Apply(extracted)
+		return &news, nil
+	}
+	if err := a.database.Insert(ctx, news, extracted); err != nil {
+		return nil, err
+	}
+	return a.database.GetFromURL(ctx, news.URL)
+}
+
+// 強制重新擷取目前的文章
+func (a *App) reextract() {
+	index := a.list.GetCurrentItem()
+	if index < 0 || index >= len(a.filteredArticles) {
+		a.showCommand("No article selected.")
+		return
+	}
+	news := a.filteredArticles[index]
+
+	ctx, done := a.startTask()
+	a.updateStatus("Extracting... (Esc to cancel)")
+	a.preview.SetText("[yellow]Loading...[white]")
+	go func() {
+		defer done()
+		if a.extractArticle(ctx, news) {
+			a.app.QueueUpdateDraw(func() {
+				a.updateStatus("Extracted.")
+			})
+		}
+	}()
 }
 
 func (a *App) showFull(news model.News, extracted *model.NewsContent) {
@@ -528,26 +819,62 @@ func (a *App) showFull(news model.News, extracted *model.NewsContent) {
 	content += fmt.Sprintf("[lightblue]Source:[white] %s\n", news.Source)
 	content += fmt.Sprintf("[lightblue]Publish:[white] %s\n", news.PublishedAt.Local().Format("2006-01-02 15:04"))
 
+	if extracted.Section != "" {
+		content += fmt.Sprintf("[lightblue]Section:[white] %s\n", extracted.Section)
+	}
+
+	if len(extracted.Keywords) > 0 {
+		content += fmt.Sprintf("[lightblue]Keywords:[white] %s\n", strings.Join(extracted.Keywords, ", "))
+	}
+
+	if extracted.Language != "" {
+		content += fmt.Sprintf("[lightblue]Language:[white] %s\n", extracted.Language)
+	}
+
 	if extracted.WordCount > 0 {
 		content += fmt.Sprintf("[lightblue]Count:[white] %d\n", extracted.WordCount)
 	}
 
-	content += fmt.Sprintf("[lightblue]Link:[white] %s\n\n", news.URL)
-	content += fmt.Sprintf("[lime]Content:[white]\n%s", a.wrapText(extracted.Content, 80))
+	if extracted.ReadingTime > 0 {
+		content += fmt.Sprintf("[lightblue]Reading:[white] %d min\n", extracted.ReadingTime)
+	}
 
-	a.preview.SetText(strings.TrimSpace(content)).ScrollToBeginning()
-}
+	if extracted.Image != "" {
+		content += fmt.Sprintf("[lightblue]Image:[white] %s\n", extracted.Image)
+	}
+
+	if extracted.Status != "" {
+		content += fmt.Sprintf("[lightblue]Extract:[white] %s\n", a.extractStatus(extracted))
+	}
 
-func (a *App) showBasicPreview(news model.News) {
-	content := fmt.Sprintf("[yellow::b]%s[white::-]\n\n", news.Title)
-	content += fmt.Sprintf("[lightblue]Source:[white] %s\n", news.Source)
-	content += fmt.Sprintf("[lightblue]Publish:[white] %s\n", news.PublishedAt.Local().Format("2006-01-02 15:04"))
 	content += fmt.Sprintf("[lightblue]Link:[white] %s\n\n", news.URL)
-	content += fmt.Sprintf("[lime]Summary:[white]\n%s", a.wrapText(strings.TrimSpace(news.Content), 80))
+	if extracted.Summary != "" {
+		content += fmt.Sprintf("[lime]Article Summary:[white]\n%s\n", a.renderMarkdown(extracted.Summary, 80))
+	}
+	if extracted.Content == "" {
+		content += fmt.Sprintf("[lime]Summary:[white]\n%s", a.wrapText(strings.TrimSpace(news.Content), 80))
+	} else {
+		content += fmt.Sprintf("[lime]Content:[white]\n%s", a.renderMarkdown(extracted.Content, 80))
+	}
 
 	a.preview.SetText(strings.TrimSpace(content)).ScrollToBeginning()
 }
 
+func (a *App) extractStatus(extracted *model.NewsContent) string {
+	color := "lime"
+	if extracted.Status != model.StatusSuccess {
+		color = "red"
+	}
+	str := fmt.Sprintf("[%s]%s[white]", color, extracted.Status)
+	if extracted.Strategy != "" {
+		str += fmt.Sprintf(" (%s, %.2f)", extracted.Strategy, extracted.Confidence)
+	}
+	if extracted.HTTPStatus != 0 {
+		str += fmt.Sprintf(" HTTP %d", extracted.HTTPStatus)
+	}
+	return str + " | Ctrl+E to re-extract"
+}
+
 func (a *App) wrapText(str string, width int) string {
 	if len(str) <= width {
 		return str
@@ -610,10 +937,10 @@ func (a *App) openBrowser(url string) {
 func (a *App) updateStatus(message string) {
 	nextCheck := ""
 	if a.autoRefresh {
-		nextCheck = fmt.Sprintf(" | Next check at: %s", time.Now().Add(5*time.Minute).Format("15:04"))
+		nextCheck = fmt.Sprintf(" | Next check at: %s", time.Now().Add(config.Get().Refresh.Interval).Format("15:04"))
 	}
 
-	nextCheck += "\n[yellow]Ctrl+R[white]: Refresh List | [yellow]Ctrl+O[white]: Open in browser"
+	nextCheck += "\n[yellow]Ctrl+R[white]: Refresh List | [yellow]Ctrl+O[white]: Open in browser | [yellow]Ctrl+S[white]: Summarize"
 
 	statusText := fmt.Sprintf("[lime]RSS Reader[white] | %s%s\n", message, nextCheck)
 	a.status.SetText(statusText)
@@ -621,7 +948,10 @@ func (a *App) updateStatus(message string) {
 
 func (a *App) Run() error {
 	defer a.database.Close()
+	defer a.lock.Release()
 	defer a.stopRefresh()
+	// 結束時取消所有進行中的擷取與請求
+	defer a.cancel()
 	a.getList(false)
 	return a.app.Run()
 }
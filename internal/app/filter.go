@@ -0,0 +1,187 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"rss-reader/internal/model"
+	"rss-reader/internal/util"
+)
+
+// 列表的篩選與排序條件
+type listFilter struct {
+	minReading int
+	// 0 表示不限
+	maxReading int
+	// 主要語言代碼，如 zh、en
+	language string
+	// "time" 依發布時間（預設），"reading" 依閱讀時間由短到長
+	sortBy string
+}
+
+func (f listFilter) active() bool {
+	return f.minReading > 0 || f.maxReading > 0 || f.language != ""
+}
+
+func (f listFilter) match(e model.News) bool {
+	if f.minReading > 0 || f.maxReading > 0 {
+		// 尚未擷取、不知道閱讀時間的文章不列入
+		minutes := e.Minutes()
+		if minutes == 0 || minutes < f.minReading || (f.maxReading > 0 && minutes > f.maxReading) {
+			return false
+		}
+	}
+	if f.language != "" && (e.Language == nil || util.PrimaryLanguage(*e.Language) != f.language) {
+		return false
+	}
+	return true
+}
+
+func (f listFilter) String() string {
+	var arr []string
+	switch {
+	case f.minReading > 0 && f.maxReading > 0:
+		arr = append(arr, fmt.Sprintf("reading %d-%d min", f.minReading, f.maxReading))
+	case f.maxReading > 0:
+		arr = append(arr, fmt.Sprintf("reading ≤ %d min", f.maxReading))
+	case f.minReading > 0:
+		arr = append(arr, fmt.Sprintf("reading ≥ %d min", f.minReading))
+	}
+	if f.language != "" {
+		arr = append(arr, "language "+f.language)
+	}
+	if f.sortBy == "reading" {
+		arr = append(arr, "sorted by reading time")
+	}
+	if len(arr) == 0 {
+		return "none"
+	}
+	return strings.Join(arr, ", ")
+}
+
+// 依篩選條件從 articles 產生 filteredArticles 並更新列表
+func (a *App) applyFilter() {
+	filtered := make([]model.News, 0, len(a.articles))
+	for _, e := range a.articles {
+		if a.filter.match(e) {
+			filtered = append(filtered, e)
+		}
+	}
+
+	if a.filter.sortBy == "reading" {
+		// 未知閱讀時間的文章排在最後
+		sort.SliceStable(filtered, func(i, j int) bool {
+			mi, mj := filtered[i].Minutes(), filtered[j].Minutes()
+			if mi == 0 || mj == 0 {
+				return mj == 0 && mi != 0
+			}
+			return mi < mj
+		})
+	}
+
+	a.filteredArticles = filtered
+	a.updateList()
+}
+
+// sort [time|reading]
+func (a *App) sortCommand(parts []string) {
+	if len(parts) < 2 || (parts[1] != "time" && parts[1] != "reading") {
+		a.showCommand("sort [time|reading]")
+		return
+	}
+	a.filter.sortBy = parts[1]
+	a.applyFilter()
+	a.updateStatus(fmt.Sprintf("Filter: %s (%d/%d)", a.filter, len(a.filteredArticles), len(a.articles)))
+}
+
+// filter reading MAX | filter reading MIN-MAX | filter lang CODE | filter clear
+func (a *App) filterCommand(parts []string) {
+	usage := "filter reading [MAX|MIN-MAX] | filter lang [CODE|all] | filter clear"
+	if len(parts) < 2 {
+		a.showCommand(fmt.Sprintf("Filter: %s\n\n%s", a.filter, usage))
+		return
+	}
+
+	switch parts[1] {
+	case "clear", "off":
+		a.filter = listFilter{sortBy: a.filter.sortBy}
+
+	case "reading":
+		if len(parts) < 3 {
+			a.showCommand(usage)
+			return
+		}
+		minReading, maxReading, err := parseRange(parts[2])
+		if err != nil {
+			a.showCommand(fmt.Sprintf("Invalid range %q: %s", parts[2], usage))
+			return
+		}
+		a.filter.minReading, a.filter.maxReading = minReading, maxReading
+
+	case "lang", "language":
+		if len(parts) < 3 {
+			a.showCommand(fmt.Sprintf("Languages: %s\n\n%s", strings.Join(a.languages(), ", "), usage))
+			return
+		}
+		a.filter.language = util.PrimaryLanguage(parts[2])
+		if a.filter.language == "all" {
+			a.filter.language = ""
+		}
+
+	default:
+		a.showCommand(usage)
+		return
+	}
+
+	a.applyFilter()
+	a.updateStatus(fmt.Sprintf("Filter: %s (%d/%d)", a.filter, len(a.filteredArticles), len(a.articles)))
+}
+
+// 列表中出現的主要語言
+func (a *App) languages() []string {
+	seen := make(map[string]bool)
+	var arr []string
+	for _, e := range a.articles {
+		if e.Language == nil {
+			continue
+		}
+		if lang := util.PrimaryLanguage(*e.Language); lang != "" && !seen[lang] {
+			seen[lang] = true
+			arr = append(arr, lang)
+		}
+	}
+	sort.Strings(arr)
+	return arr
+}
+
+// "5" 表示最多 5 分鐘，"5-10" 表示 5 到 10 分鐘，"10-" 表示至少 10 分鐘
+func parseRange(str string) (int, int, error) {
+	lower, upper, isRange := strings.Cut(str, "-")
+	if !isRange {
+		n, err := strconv.Atoi(str)
+		if err != nil || n < 1 {
+			return 0, 0, errors.New("invalid range")
+		}
+		return 0, n, nil
+	}
+
+	var minReading, maxReading int
+	var err error
+	if lower != "" {
+		if minReading, err = strconv.Atoi(lower); err != nil {
+			return 0, 0, err
+		}
+	}
+	if upper != "" {
+		if maxReading, err = strconv.Atoi(upper); err != nil {
+			return 0, 0, err
+		}
+	}
+	if minReading < 0 || maxReading < 0 || (maxReading > 0 && minReading > maxReading) || (minReading == 0 && maxReading == 0) {
+		return 0, 0, errors.New("invalid range")
+	}
+	return minReading, maxReading, nil
+}
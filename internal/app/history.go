@@ -0,0 +1,89 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+
+	"rss-reader/internal/util"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// 概要區最多可翻閱的歷史份數
+const historyLimit = 100
+
+// 概要區的按鍵：←/→ 翻閱較舊或較新的概要，d 切換與前一份的差異
+func (a *App) historyInput(event *tcell.EventKey) *tcell.EventKey {
+	switch {
+	case event.Key() == tcell.KeyLeft:
+		a.showHistory(a.historyIndex + 1)
+	case event.Key() == tcell.KeyRight:
+		a.showHistory(a.historyIndex - 1)
+	case event.Key() == tcell.KeyRune && event.Rune() == 'd':
+		a.historyDiff = !a.historyDiff
+		a.showHistory(a.historyIndex)
+	default:
+		return event
+	}
+	return nil
+}
+
+// 顯示第 index 份概要（0 為最新），第一次使用時從資料庫載入
+func (a *App) showHistory(index int) {
+	if a.history == nil {
+		list, err := a.database.Summaries(a.ctx, historyLimit)
+		if err != nil {
+			a.updateStatus(fmt.Sprintf("Failed to get summary history: %v", err))
+			return
+		}
+		a.history = list
+	}
+	if len(a.history) == 0 {
+		a.updateStatus("No summary history yet.")
+		return
+	}
+	index = max(0, min(index, len(a.history)-1))
+	a.historyIndex = index
+
+	summary := a.history[index]
+	title := fmt.Sprintf("Summary %d/%d | %s | %s | %d articles",
+		index+1, len(a.history), summary.CreatedAt.Local().Format("2006-01-02 15:04"), summary.Model, len(summary.Articles))
+
+	text := summary.Content
+	if a.historyDiff {
+		if index+1 < len(a.history) {
+			title += " | diff"
+			text = renderDiff(util.Diff(a.history[index+1].Content, summary.Content))
+		} else {
+			title += " | oldest"
+		}
+	}
+
+	a.llmView.SetTitle(title)
+	a.llmView.SetText(text).ScrollToBeginning()
+}
+
+// 新的概要產生後回到最新一份
+func (a *App) resetHistory() {
+	a.history = nil
+	a.historyIndex = 0
+	a.historyDiff = false
+	a.llmView.SetTitle("Summary")
+}
+
+func renderDiff(lines []util.DiffLine) string {
+	var b strings.Builder
+	for _, line := range lines {
+		text := tview.Escape(line.Text)
+		switch line.Op {
+		case util.DiffInsert:
+			fmt.Fprintf(&b, "[green]+ %s[white]\n", text)
+		case util.DiffDelete:
+			fmt.Fprintf(&b, "[red::s]- %s[white::-]\n", text)
+		default:
+			fmt.Fprintf(&b, "[gray]  %s[white]\n", text)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,103 @@
+package app
+
+import (
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/rivo/tview"
+)
+
+var (
+	// 換行後的強調仍可能跨越多行，因此允許比對換行
+	boldRegex      = regexp.MustCompile(`(?s)\*\*([^*\s](?:.*?[^*\s])?)\*\*`)
+	italicRegex    = regexp.MustCompile(`(?s)\*([^*\s](?:[^*]*?[^*\s])?)\*`)
+	codeRegex      = regexp.MustCompile("`([^`]+)`")
+	headingRegex   = regexp.MustCompile(`^(#{1,6})\s+(.*)$`)
+	orderedRegex   = regexp.MustCompile(`^(\d+\.)\s+(.*)$`)
+	unorderedRegex = regexp.MustCompile(`^[-*]\s+(.*)$`)
+)
+
+// 將擷取的 Markdown 轉為 tview 色彩標記
+func (a *App) renderMarkdown(str string, width int) string {
+	var result strings.Builder
+	inCode := false
+
+	for _, line := range strings.Split(str, "\n") {
+		trimmed := strings.TrimSpace(line)
+
+		if strings.HasPrefix(trimmed, "```") {
+			inCode = !inCode
+			continue
+		}
+		if inCode {
+			result.WriteString("[gray]" + tview.Escape(line) + "[-]\n")
+			continue
+		}
+
+		switch {
+		case trimmed == "":
+			result.WriteString("\n")
+
+		case trimmed == "---":
+			result.WriteString("[gray]" + strings.Repeat("─", 20) + "[-]\n")
+
+		case headingRegex.MatchString(trimmed):
+			match := headingRegex.FindStringSubmatch(trimmed)
+			color := "yellow"
+			if len(match[1]) > 2 {
+				color = "lightblue"
+			}
+			result.WriteString("[" + color + "::b]" + a.inlineMarkdown(match[2]) + "[-::-]\n")
+
+		case strings.HasPrefix(trimmed, ">"):
+			text := strings.TrimSpace(strings.TrimLeft(trimmed, "> "))
+			for _, l := range a.wrapMarkdown(text, width-2) {
+				result.WriteString("[gray]│[-] [::i]" + l + "[::-]\n")
+			}
+
+		case unorderedRegex.MatchString(trimmed):
+			text := unorderedRegex.FindStringSubmatch(trimmed)[1]
+			a.writeItem(&result, leadingSpace(line), "•", text, width)
+
+		case orderedRegex.MatchString(trimmed):
+			match := orderedRegex.FindStringSubmatch(trimmed)
+			a.writeItem(&result, leadingSpace(line), match[1], match[2], width)
+
+		default:
+			result.WriteString(strings.Join(a.wrapMarkdown(trimmed, width), "\n") + "\n")
+		}
+	}
+
+	return result.String()
+}
+
+// 列表項目換行後對齊項目符號之後的文字，巢狀列表保留原本的縮排
+func (a *App) writeItem(result *strings.Builder, prefix, marker, text string, width int) {
+	indent := prefix + strings.Repeat(" ", utf8.RuneCountInString(marker)+1)
+	for i, l := range a.wrapMarkdown(text, width-len(indent)) {
+		if i == 0 {
+			result.WriteString(prefix + "[lime]" + marker + "[-] " + l + "\n")
+		} else {
+			result.WriteString(indent + l + "\n")
+		}
+	}
+}
+
+// 先換行再套用行內標記，跨行的粗體或斜體仍能正確顯示
+func (a *App) wrapMarkdown(str string, width int) []string {
+	wrapped := strings.TrimRight(a.wrapText(str, width), "\n")
+	return strings.Split(a.inlineMarkdown(wrapped), "\n")
+}
+
+func (a *App) inlineMarkdown(str string) string {
+	str = tview.Escape(str)
+	str = boldRegex.ReplaceAllString(str, "[::b]$1[::-]")
+	str = italicRegex.ReplaceAllString(str, "[::i]$1[::-]")
+	str = codeRegex.ReplaceAllString(str, "[lime]$1[-]")
+	return str
+}
+
+func leadingSpace(line string) string {
+	return line[:len(line)-len(strings.TrimLeft(line, " \t"))]
+}
@@ -0,0 +1,59 @@
+package app
+
+import "testing"
+
+func TestRenderMarkdown(t *testing.T) {
+	tests := []struct {
+		name  string
+		in    string
+		width int
+		want  string
+	}{
+		{
+			name:  "bold and italic",
+			in:    "今天**颱風**登陸，*注意*安全",
+			width: 80,
+			want:  "今天[::b]颱風[::-]登陸，[::i]注意[::-]安全\n",
+		},
+		{
+			name:  "multiplication is not italic",
+			in:    "5 * 3 * 2",
+			width: 80,
+			want:  "5 * 3 * 2\n",
+		},
+		{
+			name:  "nested list",
+			in:    "- one\n  - one a\n    1. deep\n- two",
+			width: 80,
+			want:  "[lime]•[-] one\n  [lime]•[-] one a\n    [lime]1.[-] deep\n[lime]•[-] two\n",
+		},
+		{
+			name:  "bold across wrapped lines",
+			in:    "alpha **bravo charlie** delta",
+			width: 14,
+			want:  "alpha [::b]bravo\ncharlie[::-]\ndelta\n",
+		},
+		{
+			name:  "wrapped list item",
+			in:    "- alpha *bravo charlie*",
+			width: 14,
+			want:  "[lime]•[-] alpha [::i]bravo\n  charlie[::-]\n",
+		},
+	}
+	a := &App{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := a.renderMarkdown(tt.in, tt.width); got != tt.want {
+				t.Errorf("got\n%q\nwant\n%q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLeadingSpace(t *testing.T) {
+	for in, want := range map[string]string{"- a": "", "  - a": "  ", "\t1. a": "\t"} {
+		if got := leadingSpace(in); got != want {
+			t.Errorf("leadingSpace(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
@@ -0,0 +1,159 @@
+package app
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	"rss-reader/internal/config"
+	"rss-reader/internal/util"
+
+	"github.com/rivo/tview"
+)
+
+// prompt [list|use NAME|reset|edit [NAME]|preview [NAME]]：管理概要範本
+func (a *App) promptCommand(parts []string) {
+	sub := "list"
+	if len(parts) > 1 {
+		sub = strings.ToLower(parts[1])
+	}
+	name := ""
+	if len(parts) > 2 {
+		name = parts[2]
+	}
+
+	switch sub {
+	case "list":
+		a.showPrompts()
+
+	case "use":
+		if name == "" {
+			a.showCommand("prompt use [NAME]")
+			return
+		}
+		if !a.writable() {
+			return
+		}
+		if err := a.summarizer.UsePrompt(a.ctx, name); err != nil {
+			a.showCommand(fmt.Sprintf("Failed to use prompt: %v", err))
+			return
+		}
+		result := fmt.Sprintf("Summary prompt set to %s.", name)
+		if config.Get().Summary.Prompt != "" {
+			result += "\n\nsummary.prompt is set and replaces the instructions of every template; clear it in the config file to use this template."
+		}
+		a.showCommand(result)
+
+	case "reset":
+		if !a.writable() {
+			return
+		}
+		if err := a.summarizer.UsePrompt(a.ctx, ""); err != nil {
+			a.showCommand(fmt.Sprintf("Failed to reset prompt: %v", err))
+			return
+		}
+		a.showCommand(fmt.Sprintf("Summary prompt reset to %s (summary.template).", config.Get().Summary.Template))
+
+	case "edit":
+		if name == "" {
+			name = a.summarizer.PromptName(a.ctx)
+		}
+		a.editPrompt(name)
+
+	case "preview":
+		if name == "" {
+			name = a.summarizer.PromptName(a.ctx)
+		}
+		a.previewPrompt(name)
+
+	default:
+		a.showCommand("prompt [list|use NAME|reset|edit NAME|preview NAME]")
+	}
+}
+
+func (a *App) showPrompts() {
+	list, err := util.ListPrompts()
+	if err != nil {
+		a.showCommand(fmt.Sprintf("Failed to list prompts: %v", err))
+		return
+	}
+
+	current := a.summarizer.PromptName(a.ctx)
+	result := fmt.Sprintf("Prompts directory: %s\n\n", config.PromptsDir())
+	for _, t := range list {
+		mark := "  "
+		if t.Name == current {
+			mark = "* "
+		}
+		switch {
+		case t.Path == "":
+			result += fmt.Sprintf("%s%s (built-in)\n", mark, t.Name)
+		case t.Builtin:
+			result += fmt.Sprintf("%s%s (customized) %s\n", mark, t.Name, t.Path)
+		default:
+			result += fmt.Sprintf("%s%s %s\n", mark, t.Name, t.Path)
+		}
+	}
+	if config.Get().Summary.Prompt != "" {
+		result += "\nsummary.prompt is set and replaces the instructions of every template."
+	}
+	a.showCommand(tview.Escape(result))
+}
+
+// 以 $VISUAL 或 $EDITOR 編輯範本，內建範本或目前使用的範本會先複製到 prompts 目錄
+func (a *App) editPrompt(name string) {
+	path, err := util.PromptFile(name, a.summarizer.PromptName(a.ctx))
+	if err != nil {
+		a.showCommand(fmt.Sprintf("Failed to create prompt: %v", err))
+		return
+	}
+
+	editor := os.Getenv("VISUAL")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	if editor == "" {
+		editor = "vi"
+		if runtime.GOOS == "windows" {
+			editor = "notepad"
+		}
+	}
+
+	// 編輯器可能帶有參數，如 "code -w"
+	args := append(strings.Fields(editor), path)
+	a.app.Suspend(func() {
+		cmd := exec.Command(args[0], args[1:]...)
+		cmd.Stdin = os.Stdin
+		cmd.Stdout = os.Stdout
+		cmd.Stderr = os.Stderr
+		err = cmd.Run()
+	})
+	if err != nil {
+		a.showCommand(fmt.Sprintf("Failed to run editor %s: %v\n\nEdit %s manually.", editor, err, path))
+		return
+	}
+	a.previewPrompt(name)
+}
+
+// 以目前的文章與前次概要產生系統提示詞，確認範本內容
+func (a *App) previewPrompt(name string) {
+	articles := a.articles
+	a.showCommand("[yellow]Loading...[white]")
+
+	go func() {
+		text, count, tokens, err := a.summarizer.PreviewPrompt(a.ctx, name, articles)
+		a.app.QueueUpdateDraw(func() {
+			if err != nil {
+				a.showCommand(tview.Escape(err.Error()))
+				return
+			}
+			header := fmt.Sprintf("[lime]Prompt:[white] %s | %d articles | ~%d tokens (budget %d)\n\n", name, count, tokens, config.Get().Summary.Budget)
+			if config.Get().Summary.Prompt != "" {
+				header += "[yellow]summary.prompt is set and replaces the instructions of this template.[white]\n\n"
+			}
+			a.showCommand(header + tview.Escape(text))
+		})
+	}()
+}
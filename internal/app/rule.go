@@ -0,0 +1,134 @@
+package app
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"rss-reader/internal/config"
+	"rss-reader/internal/util"
+
+	"github.com/rivo/tview"
+)
+
+// 允許選擇器包含空白，以下一個 key= 作為分隔
+var ruleArgRegex = regexp.MustCompile(`(?:^|\s)(host|content|title|author|strip)=`)
+
+func (a *App) ruleCommand(parts []string, command string) {
+	sub := "list"
+	if len(parts) > 1 {
+		sub = strings.ToLower(parts[1])
+	}
+
+	switch sub {
+	case "list":
+		a.showRules()
+
+	case "test":
+		if len(parts) < 3 {
+			a.showCommand("rule test [URL] content=SELECTOR title=SELECTOR author=SELECTOR strip=SELECTOR,SELECTOR")
+			return
+		}
+		link := parts[2]
+		u, err := url.Parse(link)
+		if err != nil || u.Hostname() == "" {
+			a.showCommand(fmt.Sprintf("Invalid URL: %s", link))
+			return
+		}
+
+		rule := parseRuleArgs(command[strings.Index(command, link)+len(link):])
+		if rule.Host == "" {
+			rule.Host = u.Hostname()
+		}
+		a.testRule(link, rule)
+
+	case "save":
+		if a.pendingRule == nil {
+			a.showCommand("No tested rule. Run rule test [URL] ... first.")
+			return
+		}
+		if err := util.SaveRule(config.RulesPath(), *a.pendingRule); err != nil {
+			a.showCommand(fmt.Sprintf("Failed to save rule: %v", err))
+			return
+		}
+		if err := a.extractor.ReloadRules(); err != nil {
+			a.showCommand(fmt.Sprintf("Failed to reload rules: %v", err))
+			return
+		}
+		a.showCommand(fmt.Sprintf("Rule saved to %s\n\n%s", config.RulesPath(), a.pendingRule.String()))
+		a.pendingRule = nil
+
+	default:
+		a.showCommand("rule [list|test|save]")
+	}
+}
+
+func parseRuleArgs(str string) util.Rule {
+	var rule util.Rule
+	matches := ruleArgRegex.FindAllStringSubmatchIndex(str, -1)
+	for i, m := range matches {
+		end := len(str)
+		if i+1 < len(matches) {
+			end = matches[i+1][0]
+		}
+		key := str[m[2]:m[3]]
+		value := strings.TrimSpace(str[m[1]:end])
+
+		switch key {
+		case "host":
+			rule.Host = value
+		case "content":
+			rule.Content = value
+		case "title":
+			rule.Title = value
+		case "author":
+			rule.Author = value
+		case "strip":
+			for _, selector := range strings.Split(value, ",") {
+				if selector = strings.TrimSpace(selector); selector != "" {
+					rule.Strip = append(rule.Strip, selector)
+				}
+			}
+		}
+	}
+	return rule
+}
+
+// 以規則擷取頁面並顯示於預覽區，確認後以 rule save 儲存
+func (a *App) testRule(link string, rule util.Rule) {
+	a.preview.SetText("[yellow]Testing rule...[white]")
+
+	go func() {
+		extracted, err := a.extractor.GetWithRule(a.ctx, link, &rule)
+		a.app.QueueUpdateDraw(func() {
+			if err != nil {
+				a.showCommand(fmt.Sprintf("Failed to get content %s: %v", link, err))
+				return
+			}
+			a.pendingRule = &rule
+
+			content := fmt.Sprintf("[yellow::b]Rule test: %s[white::-]\n", tview.Escape(rule.String()))
+			content += "[gray]Run \"rule save\" to keep this rule.[white]\n\n"
+			content += fmt.Sprintf("[lightblue]Title:[white] %s\n", tview.Escape(extracted.Title))
+			if extracted.Author != "" {
+				content += fmt.Sprintf("[lightblue]Author:[white] %s\n", tview.Escape(extracted.Author))
+			}
+			content += fmt.Sprintf("[lightblue]Count:[white] %d\n\n", extracted.WordCount)
+			content += fmt.Sprintf("[lime]Content:[white]\n%s", a.renderMarkdown(extracted.Content, 80))
+			a.preview.SetText(strings.TrimSpace(content)).ScrollToBeginning()
+		})
+	}()
+}
+
+func (a *App) showRules() {
+	rules := a.extractor.Rules()
+	result := fmt.Sprintf("Rules file: %s\n\n", config.RulesPath())
+	if len(rules) == 0 {
+		result += "No site rules."
+	}
+	for i, rule := range rules {
+		result += fmt.Sprintf("%d. %s\n", i+1, rule.String())
+	}
+	a.showCommand(result)
+}
@@ -0,0 +1,68 @@
+package app
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+var spinnerFrames = []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
+
+// 狀態列的載入動畫
+type spinner struct {
+	mu      sync.Mutex
+	message string
+	stopped bool
+	done    chan struct{}
+}
+
+// 在狀態列顯示動畫與訊息，直到呼叫 stop 或 ctx 中止
+func (a *App) startSpinner(ctx context.Context, message string) *spinner {
+	s := &spinner{message: message, done: make(chan struct{})}
+
+	go func() {
+		ticker := time.NewTicker(redrawInterval)
+		defer ticker.Stop()
+
+		for i := 0; ; i++ {
+			select {
+			case <-ctx.Done():
+				return
+			case <-s.done:
+				return
+			case <-ticker.C:
+			}
+
+			frame := spinnerFrames[i%len(spinnerFrames)]
+			a.app.QueueUpdateDraw(func() {
+				// 已停止或中止時不覆蓋最終的狀態訊息
+				message, ok := s.current()
+				if ok && ctx.Err() == nil {
+					a.updateStatus(frame + " " + message)
+				}
+			})
+		}
+	}()
+	return s
+}
+
+func (s *spinner) set(message string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.message = message
+}
+
+func (s *spinner) current() (string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.message, !s.stopped
+}
+
+func (s *spinner) stop() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.stopped {
+		s.stopped = true
+		close(s.done)
+	}
+}
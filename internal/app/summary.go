@@ -0,0 +1,93 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"rss-reader/internal/api"
+)
+
+// 產生目前文章的條列概要，已有概要時直接顯示
+func (a *App) summarizeArticle() {
+	index := a.list.GetCurrentItem()
+	if index < 0 || index >= len(a.filteredArticles) {
+		a.showCommand("No article selected.")
+		return
+	}
+	news := a.filteredArticles[index]
+
+	ctx, done := a.startTask()
+	a.updateStatus("Summarizing... (Esc to cancel)")
+	a.preview.SetText("[yellow]Loading...[white]")
+
+	go func() {
+		defer done()
+
+		stored, err := a.storedArticle(ctx, news)
+		if err != nil {
+			if ctx.Err() == nil {
+				a.app.QueueUpdateDraw(func() {
+					a.updateStatus(fmt.Sprintf("Failed to get content: %v", err))
+				})
+			}
+			return
+		}
+		news.PublishedAt = stored.PublishedAt
+		extracted := stored.Extracted()
+
+		if extracted.Summary != "" {
+			a.app.QueueUpdateDraw(func() {
+				a.showFull(news, extracted)
+				a.updateStatus("Summary loaded.")
+			})
+			return
+		}
+
+		var text strings.Builder
+		var last time.Time
+		summary, err := a.summarizer.Article(ctx, *stored, func(delta string) {
+			text.WriteString(delta)
+			if time.Since(last) < redrawInterval {
+				return
+			}
+			last = time.Now()
+			partial := *extracted
+			partial.Summary = text.String()
+			a.app.QueueUpdateDraw(func() {
+				a.showFull(news, &partial)
+			})
+		})
+		if ctx.Err() != nil {
+			return
+		}
+
+		a.app.QueueUpdateDraw(func() {
+			if err != nil {
+				a.updateStatus(llmStatus("summarize", err))
+				return
+			}
+			extracted.Summary = summary
+			a.showFull(news, extracted)
+			a.updateStatus("Summary generated.")
+		})
+	}()
+}
+
+// 依 LLM 錯誤類型顯示可讀的狀態訊息
+func llmStatus(action string, err error) string {
+	switch {
+	case errors.Is(err, api.ErrAuth):
+		return "[red]LLM authentication failed[white], check the key (apikey) and llm.provider"
+	case errors.Is(err, api.ErrRateLimit):
+		return "[red]LLM rate limit reached[white], try again later"
+	case errors.Is(err, api.ErrContextLength):
+		return "[red]Too much text for the model[white], lower summary.budget"
+	case errors.Is(err, api.ErrBudget):
+		return "[red]Monthly LLM budget reached[white], raise llm.monthly_budget or wait for next month"
+	case errors.Is(err, api.ErrServer):
+		return "[red]LLM service unavailable[white], try again later"
+	}
+	return fmt.Sprintf("Failed to %s: %v", action, err)
+}
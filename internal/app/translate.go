@@ -0,0 +1,87 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"rss-reader/internal/model"
+	"rss-reader/internal/util"
+)
+
+// 串流時重新繪製預覽的最短間隔
+const redrawInterval = 100 * time.Millisecond
+
+// translate [lang]：翻譯目前文章並串流顯示於預覽區
+func (a *App) translate(parts []string) {
+	index := a.list.GetCurrentItem()
+	if index < 0 || index >= len(a.filteredArticles) {
+		a.showCommand("No article selected.")
+		return
+	}
+	news := a.filteredArticles[index]
+	target := ""
+	if len(parts) > 1 {
+		target = parts[1]
+	}
+
+	ctx, done := a.startTask()
+	a.updateStatus("Translating... (Esc to cancel)")
+	a.preview.SetText("[yellow]Loading...[white]")
+
+	go func() {
+		defer done()
+
+		stored, err := a.storedArticle(ctx, news)
+		if err != nil {
+			if ctx.Err() == nil {
+				a.app.QueueUpdateDraw(func() {
+					a.updateStatus(fmt.Sprintf("Failed to get content: %v", err))
+				})
+			}
+			return
+		}
+
+		target = util.TranslateTarget(*stored, target)
+		var text strings.Builder
+		var last time.Time
+		_, err = a.translator.Translate(ctx, *stored, target, func(delta string) {
+			text.WriteString(delta)
+			if time.Since(last) < redrawInterval {
+				return
+			}
+			last = time.Now()
+			current := text.String()
+			a.app.QueueUpdateDraw(func() {
+				a.showTranslation(*stored, target, current)
+			})
+		})
+		if ctx.Err() != nil {
+			return
+		}
+
+		current := text.String()
+		a.app.QueueUpdateDraw(func() {
+			if err != nil {
+				a.updateStatus(llmStatus("translate", err))
+				return
+			}
+			a.showTranslation(*stored, target, current)
+			a.updateStatus(fmt.Sprintf("Translated into %s.", target))
+		})
+	}()
+}
+
+func (a *App) showTranslation(news model.News, target, text string) {
+	content := fmt.Sprintf("[yellow::b]%s[white::-]\n\n", news.Title)
+	content += fmt.Sprintf("[lightblue]Source:[white] %s\n", news.Source)
+	if news.Language != nil {
+		content += fmt.Sprintf("[lightblue]Language:[white] %s → %s\n", *news.Language, target)
+	} else {
+		content += fmt.Sprintf("[lightblue]Language:[white] %s\n", target)
+	}
+	content += fmt.Sprintf("[lightblue]Link:[white] %s\n\n", news.URL)
+	content += fmt.Sprintf("[lime]Translation:[white]\n%s", a.renderMarkdown(text, 80))
+
+	a.preview.SetText(strings.TrimSpace(content))
+}
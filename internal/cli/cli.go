@@ -0,0 +1,533 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+
+	"rss-reader/internal/config"
+	"rss-reader/internal/daemon"
+	"rss-reader/internal/database"
+	"rss-reader/internal/model"
+	"rss-reader/internal/util"
+)
+
+const usageText = `Usage: rss-reader [--config FILE] [command] [flags]
+
+Without a command the terminal UI is started. The config file defaults to
+$RSS_CONFIG_PATH or $XDG_CONFIG_HOME/rss-reader/config.toml.
+
+Commands:
+  fetch                         Fetch feeds and store new articles
+  list [--hours N] [--source X] [--lang L] [--max-reading N] [--sort time|reading]
+                                List stored articles
+  add <URL>...                  Add RSS feeds
+  remove <URL>...               Remove RSS feeds (alias: rm)
+  feeds                         List RSS feeds
+  show [--summarize] <URL>      Show an article, extracting it if needed
+  translate [--to L] <URL>      Translate an article, cached per language
+  summary [--generate] [--history N]
+                                Print the latest summary, or the last N
+  archive <URL>...              Save offline snapshots with images
+  usage                         Show LLM token usage and estimated cost
+  serve [--interval D] [--log F] Collect in the background without a UI (alias: daemon)
+
+Every command accepts --json for machine-readable output.
+`
+
+type CLI struct {
+	database   *database.SQLite
+	collector  *util.Collector
+	extractor  *util.Extractor
+	updater    *util.Updater
+	summarizer *util.Summarizer
+	out        io.Writer
+}
+
+// 判斷參數是否為子指令，非子指令時由呼叫端啟動 TUI
+func IsCommand(name string) bool {
+	switch name {
+	case "fetch", "list", "add", "remove", "rm", "feeds", "show", "translate", "summary", "archive", "usage", "serve", "daemon", "help", "-h", "--help":
+		return true
+	}
+	return false
+}
+
+func Run(args []string) error {
+	if len(args) == 0 || !IsCommand(args[0]) {
+		return fmt.Errorf("unknown command: %s", strings.Join(args, " "))
+	}
+
+	switch args[0] {
+	case "help", "-h", "--help":
+		fmt.Print(usageText)
+		return nil
+	case "serve", "daemon":
+		return daemon.Run(args[1:])
+	}
+
+	db, err := database.NewSQLite()
+	if err != nil {
+		return fmt.Errorf("failed to init SQLite: %w", err)
+	}
+	defer db.Close()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	collector := util.NewCollector(db)
+	extractor := util.NewExtractor()
+	c := &CLI{
+		database:   db,
+		collector:  collector,
+		extractor:  extractor,
+		updater:    util.NewUpdater(db, collector, extractor),
+		summarizer: util.NewSummarizer(db),
+		out:        os.Stdout,
+	}
+
+	cmd, rest := args[0], args[1:]
+	switch cmd {
+	case "fetch":
+		return c.fetch(ctx, rest)
+	case "list":
+		return c.list(ctx, rest)
+	case "add":
+		return c.add(ctx, rest)
+	case "remove", "rm":
+		return c.remove(ctx, rest)
+	case "feeds":
+		return c.feeds(ctx, rest)
+	case "show":
+		return c.show(ctx, rest)
+	case "translate":
+		return c.translate(ctx, rest)
+	case "summary":
+		return c.summary(ctx, rest)
+	case "archive":
+		return c.archive(ctx, rest)
+	case "usage":
+		return c.usage(ctx, rest)
+	}
+	return nil
+}
+
+func newFlagSet(name string) (*flag.FlagSet, *bool) {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	isJSON := fs.Bool("json", false, "output JSON")
+	return fs, isJSON
+}
+
+func (c *CLI) fetch(ctx context.Context, args []string) error {
+	fs, isJSON := newFlagSet("fetch")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	lock, err := util.AcquireLock(util.LockPath(c.database.Path()))
+	if err != nil {
+		return err
+	}
+	defer lock.Release()
+
+	articles, newCount, err := c.updater.Check(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get news list: %w", err)
+	}
+
+	if newCount > 0 {
+		if err := c.updater.Load(ctx, articles, nil); err != nil {
+			return err
+		}
+	}
+
+	if *isJSON {
+		return c.json(map[string]int{
+			"total": len(articles),
+			"new":   newCount,
+		})
+	}
+	fmt.Fprintf(c.out, "Fetched %d articles, %d new\n", len(articles), newCount)
+	return nil
+}
+
+func (c *CLI) list(ctx context.Context, args []string) error {
+	fs, isJSON := newFlagSet("list")
+	hours := fs.Int("hours", config.Hours(config.Get().Retention.Feed), "only list articles published within N hours")
+	source := fs.String("source", "", "only list articles whose source contains X")
+	lang := fs.String("lang", "", "only list articles in language L, e.g. zh or en")
+	maxReading := fs.Int("max-reading", 0, "only list articles that take at most N minutes to read")
+	sortBy := fs.String("sort", "time", "sort by time or reading")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *sortBy != "time" && *sortBy != "reading" {
+		return fmt.Errorf("invalid sort %q", *sortBy)
+	}
+
+	articles, err := c.database.Get(ctx, *hours)
+	if err != nil {
+		return err
+	}
+
+	filtered := make([]model.News, 0, len(articles))
+	for _, e := range articles {
+		if *source != "" && !strings.Contains(strings.ToLower(e.Source), strings.ToLower(*source)) {
+			continue
+		}
+		if *lang != "" && (e.Language == nil || util.PrimaryLanguage(*e.Language) != util.PrimaryLanguage(*lang)) {
+			continue
+		}
+		if *maxReading > 0 && (e.Minutes() == 0 || e.Minutes() > *maxReading) {
+			continue
+		}
+		filtered = append(filtered, e)
+	}
+
+	if *sortBy == "reading" {
+		sort.SliceStable(filtered, func(i, j int) bool {
+			return filtered[i].Minutes() < filtered[j].Minutes()
+		})
+	}
+
+	if *isJSON {
+		return c.json(filtered)
+	}
+	for _, e := range filtered {
+		title := e.Title
+		if e.IsBlocked() {
+			title = fmt.Sprintf("[%s] %s", e.Status, title)
+		}
+		reading := ""
+		if minutes := e.Minutes(); minutes > 0 {
+			reading = fmt.Sprintf(" | %d min", minutes)
+		}
+		fmt.Fprintf(c.out, "%s | %s%s | %s\n  %s\n", e.PublishedAt.Local().Format("01/02 15:04"), e.Source, reading, title, e.URL)
+	}
+	return nil
+}
+
+func (c *CLI) add(ctx context.Context, args []string) error {
+	fs, isJSON := newFlagSet("add")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		return errors.New("add [URL]")
+	}
+
+	if err := c.database.InsertFeeds(ctx, fs.Args()); err != nil {
+		return fmt.Errorf("failed to add RSS: %w", err)
+	}
+
+	if *isJSON {
+		return c.json(fs.Args())
+	}
+	for _, url := range fs.Args() {
+		fmt.Fprintf(c.out, "Add RSS: %s\n", url)
+	}
+	return nil
+}
+
+func (c *CLI) remove(ctx context.Context, args []string) error {
+	fs, isJSON := newFlagSet("remove")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		return errors.New("remove [URL]")
+	}
+
+	for _, url := range fs.Args() {
+		if err := c.collector.Remove(ctx, url); err != nil {
+			return fmt.Errorf("failed to remove RSS %s: %w", url, err)
+		}
+	}
+
+	if *isJSON {
+		return c.json(fs.Args())
+	}
+	for _, url := range fs.Args() {
+		fmt.Fprintf(c.out, "Remove RSS: %s\n", url)
+	}
+	return nil
+}
+
+func (c *CLI) feeds(ctx context.Context, args []string) error {
+	fs, isJSON := newFlagSet("feeds")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	feeds, err := c.collector.List(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list RSS feed: %w", err)
+	}
+
+	if *isJSON {
+		if feeds == nil {
+			feeds = []string{}
+		}
+		return c.json(feeds)
+	}
+	for _, feed := range feeds {
+		fmt.Fprintln(c.out, feed)
+	}
+	return nil
+}
+
+func (c *CLI) show(ctx context.Context, args []string) error {
+	fs, isJSON := newFlagSet("show")
+	summarize := fs.Bool("summarize", false, "generate a bullet summary if none is cached")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		return errors.New("show [URL]")
+	}
+	url := fs.Arg(0)
+
+	news, err := c.article(ctx, url)
+	if err != nil {
+		return err
+	}
+	if *summarize && news.Summary == nil {
+		summary, err := c.summarizer.Article(ctx, *news, nil)
+		if err != nil {
+			return err
+		}
+		news.Summary = &summary
+	}
+
+	if *isJSON {
+		return c.json(news)
+	}
+
+	fmt.Fprintf(c.out, "%s\n\n", news.Title)
+	if news.Author != nil {
+		fmt.Fprintf(c.out, "Author: %s\n", *news.Author)
+	}
+	if news.Source != "" {
+		fmt.Fprintf(c.out, "Source: %s\n", news.Source)
+	}
+	if !news.PublishedAt.IsZero() {
+		fmt.Fprintf(c.out, "Publish: %s\n", news.PublishedAt.Local().Format("2006-01-02 15:04"))
+	}
+	if news.Section != nil {
+		fmt.Fprintf(c.out, "Section: %s\n", *news.Section)
+	}
+	if len(news.Keywords) > 0 {
+		fmt.Fprintf(c.out, "Keywords: %s\n", strings.Join(news.Keywords, ", "))
+	}
+	if news.Language != nil {
+		fmt.Fprintf(c.out, "Language: %s\n", *news.Language)
+	}
+	if news.WordCount != nil {
+		fmt.Fprintf(c.out, "Count: %d\n", *news.WordCount)
+	}
+	if news.ReadingTime != nil {
+		fmt.Fprintf(c.out, "Reading: %d min\n", *news.ReadingTime)
+	}
+	if news.Image != nil {
+		fmt.Fprintf(c.out, "Image: %s\n", *news.Image)
+	}
+	if news.Status != "" {
+		fmt.Fprintf(c.out, "Extract: %s\n", extractStatus(*news))
+	}
+	fmt.Fprintf(c.out, "Link: %s\n", news.URL)
+	if news.Summary != nil {
+		fmt.Fprintf(c.out, "\n%s\n", *news.Summary)
+	}
+	if news.FullContent != nil {
+		fmt.Fprintf(c.out, "\n%s\n", *news.FullContent)
+	}
+	return nil
+}
+
+// 讀取文章，尚未擷取時即時擷取並寫回；資料庫中沒有的網址只擷取不儲存
+func (c *CLI) article(ctx context.Context, url string) (*model.News, error) {
+	news, err := c.database.GetFromURL(ctx, url)
+	stored := err == nil
+	if !stored {
+		news = &model.News{URL: url}
+	}
+	if news.IsExtracted() {
+		return news, nil
+	}
+
+	extracted, err := c.extractor.Extract(ctx, *news)
+	if extracted == nil {
+		return nil, fmt.Errorf("failed to get content %s: %w", url, err)
+	}
+	if !stored {
+		news.Apply(extracted)
+		return news, nil
+	}
+	if news.Title == "" {
+		news.Title = extracted.Title
+	}
+	if news.PublishedAt.IsZero() {
+		news.PublishedAt = extracted.PublishedAt
+	}
+	if err := c.database.Insert(ctx, *news, extracted); err != nil {
+		return nil, fmt.Errorf("failed to store news %s: %w", url, err)
+	}
+	return c.database.GetFromURL(ctx, url)
+}
+
+func (c *CLI) translate(ctx context.Context, args []string) error {
+	fs, isJSON := newFlagSet("translate")
+	to := fs.String("to", "", "target language, defaults to English for Chinese articles and zh-TW otherwise")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		return errors.New("translate [URL]")
+	}
+
+	news, err := c.article(ctx, fs.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	target := util.TranslateTarget(*news, *to)
+	translator := util.NewTranslator(c.database)
+	translation, err := translator.Translate(ctx, *news, target, func(delta string) {
+		if !*isJSON {
+			fmt.Fprint(c.out, delta)
+		}
+	})
+	if err != nil {
+		return err
+	}
+
+	if *isJSON {
+		return c.json(map[string]string{
+			"url":         news.URL,
+			"language":    target,
+			"translation": translation,
+		})
+	}
+	fmt.Fprintln(c.out)
+	return nil
+}
+
+func extractStatus(news model.News) string {
+	str := news.Status
+	if news.Strategy != "" {
+		str += fmt.Sprintf(" (%s, %.2f)", news.Strategy, news.Confidence)
+	}
+	if news.HTTPStatus != 0 {
+		str += fmt.Sprintf(" HTTP %d", news.HTTPStatus)
+	}
+	return str
+}
+
+func (c *CLI) summary(ctx context.Context, args []string) error {
+	fs, isJSON := newFlagSet("summary")
+	generate := fs.Bool("generate", false, "generate a new summary from the last 24 hours")
+	history := fs.Int("history", 0, "list the last N summaries instead")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *history > 0 {
+		list, err := c.database.Summaries(ctx, *history)
+		if err != nil {
+			return err
+		}
+		if *isJSON {
+			if list == nil {
+				list = []database.Summary{}
+			}
+			return c.json(list)
+		}
+		for _, summary := range list {
+			fmt.Fprintf(c.out, "%s  %s  %d articles\n\n%s\n\n", summary.CreatedAt.Local().Format("2006-01-02 15:04"), summary.Model, len(summary.Articles), summary.Content)
+		}
+		return nil
+	}
+
+	var summary string
+	if *generate {
+		lock, err := util.AcquireLock(util.LockPath(c.database.Path()))
+		if err != nil {
+			return err
+		}
+		defer lock.Release()
+
+		news, err := c.database.Get(ctx, config.Hours(config.Get().Retention.Summary))
+		if err != nil {
+			return err
+		}
+		if summary, err = c.summarizer.Generate(ctx, news, nil, nil); err != nil {
+			return err
+		}
+	} else {
+		summary, _ = c.database.GetKey(ctx, "summary")
+	}
+
+	if *isJSON {
+		return c.json(map[string]string{"summary": summary})
+	}
+	fmt.Fprintln(c.out, summary)
+	return nil
+}
+
+func (c *CLI) archive(ctx context.Context, args []string) error {
+	fs, isJSON := newFlagSet("archive")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		return errors.New("archive [URL]")
+	}
+
+	archiver := util.NewArchiver(c.database)
+	paths := make(map[string]string)
+	for _, url := range fs.Args() {
+		p, err := archiver.Archive(ctx, url)
+		if err != nil {
+			return fmt.Errorf("failed to archive %s: %w", url, err)
+		}
+		paths[url] = p
+	}
+
+	if *isJSON {
+		return c.json(paths)
+	}
+	for _, url := range fs.Args() {
+		fmt.Fprintf(c.out, "%s\n  %s\n", url, paths[url])
+	}
+	return nil
+}
+
+func (c *CLI) json(v any) error {
+	encoder := json.NewEncoder(c.out)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(v)
+}
+
+func (c *CLI) usage(ctx context.Context, args []string) error {
+	fs, isJSON := newFlagSet("usage")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	report, err := util.NewUsageReport(ctx, c.database)
+	if err != nil {
+		return err
+	}
+	if *isJSON {
+		return c.json(report)
+	}
+	fmt.Fprint(c.out, report)
+	return nil
+}
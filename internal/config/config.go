@@ -0,0 +1,374 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/BurntSushi/toml"
+)
+
+type Config struct {
+	Database  Database  `toml:"database"`
+	Refresh   Refresh   `toml:"refresh"`
+	Retention Retention `toml:"retention"`
+	HTTP      HTTP      `toml:"http"`
+	Extractor Extractor `toml:"extractor"`
+	Archive   Archive   `toml:"archive"`
+	LLM       LLM       `toml:"llm"`
+	UI        UI        `toml:"ui"`
+	Summary   Summary   `toml:"summary"`
+}
+
+type Database struct {
+	// 空字串時依執行環境決定 rss.db 位置
+	Path string `toml:"path"`
+}
+
+type Refresh struct {
+	Interval time.Duration `toml:"interval"`
+}
+
+type Retention struct {
+	// 超過此時間的 RSS 項目不收錄，也是列表顯示的範圍
+	Feed time.Duration `toml:"feed"`
+	// 沒有前次概要時，納入概要的文章範圍
+	Summary time.Duration `toml:"summary"`
+}
+
+type HTTP struct {
+	Timeout time.Duration `toml:"timeout"`
+}
+
+type Extractor struct {
+	// 同一網站兩次請求的最小間隔
+	Delay time.Duration `toml:"delay"`
+	// 同時擷取的文章數
+	Workers int `toml:"workers"`
+	// 連線錯誤、429 與 5xx 的重試次數
+	Retries int `toml:"retries"`
+	// 網站擷取規則檔，空字串時使用設定檔同目錄的 rules.toml
+	Rules string `toml:"rules"`
+	// 分頁文章最多抓取的頁數
+	MaxPages int `toml:"max_pages"`
+}
+
+type Archive struct {
+	// 擷取新文章時同時保存離線快照
+	Enabled bool `toml:"enabled"`
+	// 單篇快照（HTML 與圖片）的大小上限，單位 MB
+	MaxSize int `toml:"max_size"`
+}
+
+type LLM struct {
+	// openai、openai-compatible、anthropic 或 ollama
+	Provider string `toml:"provider"`
+	// 空字串時使用 provider 的預設位址
+	BaseURL    string `toml:"base_url"`
+	SmallModel string `toml:"small_model"`
+	LargeModel string `toml:"large_model"`
+	// 限流、伺服器與連線錯誤的重試次數
+	Retries int `toml:"retries"`
+	// 每月花費上限（美元），0 表示不限制
+	MonthlyBudget float64 `toml:"monthly_budget"`
+	// 依模型名稱的價格表，估算花費使用
+	Prices map[string]Price `toml:"prices"`
+}
+
+// 每百萬 token 的價格（美元）
+type Price struct {
+	Input  float64 `toml:"input"`
+	Output float64 `toml:"output"`
+}
+
+type UI struct {
+	ListHeight int `toml:"list_height"`
+	// 預覽時在正文上方顯示已產生的單篇概要
+	ArticleSummary bool `toml:"article_summary"`
+}
+
+type Summary struct {
+	// 概要範本名稱，對應 prompts 目錄中的 NAME.tmpl 或內建的 zh-TW、en
+	Template string `toml:"template"`
+	// 非空時取代範本中的指令段落（舊版設定）
+	Prompt string `toml:"prompt"`
+	// 單次請求的輸入 token 上限，超過時分批整理後再合併
+	Budget int `toml:"budget"`
+}
+
+func Default() *Config {
+	return &Config{
+		Refresh: Refresh{
+			Interval: 5 * time.Minute,
+		},
+		Retention: Retention{
+			Feed:    72 * time.Hour,
+			Summary: 24 * time.Hour,
+		},
+		HTTP: HTTP{
+			Timeout: 30 * time.Second,
+		},
+		Extractor: Extractor{
+			Delay:    500 * time.Millisecond,
+			Workers:  4,
+			Retries:  3,
+			MaxPages: 5,
+		},
+		Archive: Archive{
+			MaxSize: 20,
+		},
+		LLM: LLM{
+			Provider:   "openai",
+			SmallModel: "gpt-4o-mini",
+			LargeModel: "gpt-4o",
+			Retries:    3,
+			Prices: map[string]Price{
+				"gpt-4o-mini": {Input: 0.15, Output: 0.6},
+				"gpt-4o":      {Input: 2.5, Output: 10},
+			},
+		},
+		UI: UI{
+			ListHeight:     18,
+			ArticleSummary: true,
+		},
+		Summary: Summary{
+			Template: "zh-TW",
+			Budget:   12000,
+		},
+	}
+}
+
+var (
+	current atomic.Pointer[Config]
+	mu      sync.Mutex
+	path    string
+)
+
+// 取得目前設定，尚未載入時回傳預設值
+func Get() *Config {
+	if c := current.Load(); c != nil {
+		return c
+	}
+	return Default()
+}
+
+// 設定檔路徑：參數 > RSS_CONFIG_PATH > $XDG_CONFIG_HOME/rss-reader/config.toml
+func Path(flagPath string) string {
+	if flagPath != "" {
+		return flagPath
+	}
+	if envPath := os.Getenv("RSS_CONFIG_PATH"); envPath != "" {
+		return envPath
+	}
+	return defaultPath()
+}
+
+// XDG 預設設定檔路徑，只有這個路徑允許不存在
+func defaultPath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "rss-reader", "config.toml")
+}
+
+// 讀取設定檔並套用環境變數，預設路徑的檔案不存在時使用預設值
+func Load(filePath string) error {
+	mu.Lock()
+	defer mu.Unlock()
+
+	c, err := read(filePath)
+	if err != nil {
+		return err
+	}
+	path = filePath
+	current.Store(c)
+	return nil
+}
+
+// 以上次載入的路徑重新讀取設定
+func Reload() error {
+	mu.Lock()
+	defer mu.Unlock()
+
+	c, err := read(path)
+	if err != nil {
+		return err
+	}
+	current.Store(c)
+	return nil
+}
+
+// 目前使用的設定檔路徑
+func File() string {
+	mu.Lock()
+	defer mu.Unlock()
+	return path
+}
+
+func read(filePath string) (*Config, error) {
+	c := Default()
+
+	if filePath != "" {
+		_, err := toml.DecodeFile(filePath, c)
+		if errors.Is(err, os.ErrNotExist) && filePath == defaultPath() {
+			err = nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read config %s: %w", filePath, err)
+		}
+	}
+
+	if err := c.env(); err != nil {
+		return nil, err
+	}
+	if err := c.validate(); err != nil {
+		return nil, fmt.Errorf("invalid config %s: %w", filePath, err)
+	}
+	return c, nil
+}
+
+// 環境變數優先於設定檔
+func (c *Config) env() error {
+	durations := map[string]*time.Duration{
+		"RSS_REFRESH_INTERVAL":  &c.Refresh.Interval,
+		"RSS_FEED_RETENTION":    &c.Retention.Feed,
+		"RSS_SUMMARY_RETENTION": &c.Retention.Summary,
+		"RSS_HTTP_TIMEOUT":      &c.HTTP.Timeout,
+		"RSS_EXTRACTOR_DELAY":   &c.Extractor.Delay,
+	}
+	for key, field := range durations {
+		value := os.Getenv(key)
+		if value == "" {
+			continue
+		}
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return fmt.Errorf("invalid %s: %w", key, err)
+		}
+		*field = d
+	}
+
+	texts := map[string]*string{
+		"RSS_DB_PATH":          &c.Database.Path,
+		"RSS_SMALL_MODEL":      &c.LLM.SmallModel,
+		"RSS_LARGE_MODEL":      &c.LLM.LargeModel,
+		"RSS_LLM_PROVIDER":     &c.LLM.Provider,
+		"RSS_LLM_BASE_URL":     &c.LLM.BaseURL,
+		"RSS_SUMMARY_TEMPLATE": &c.Summary.Template,
+	}
+	for key, field := range texts {
+		if value := os.Getenv(key); value != "" {
+			*field = value
+		}
+	}
+
+	if value := os.Getenv("RSS_ARCHIVE"); value != "" {
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid RSS_ARCHIVE: %w", err)
+		}
+		c.Archive.Enabled = b
+	}
+
+	ints := map[string]*int{
+		"RSS_LIST_HEIGHT":       &c.UI.ListHeight,
+		"RSS_EXTRACTOR_WORKERS": &c.Extractor.Workers,
+		"RSS_SUMMARY_BUDGET":    &c.Summary.Budget,
+	}
+	for key, field := range ints {
+		value := os.Getenv(key)
+		if value == "" {
+			continue
+		}
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("invalid %s: %w", key, err)
+		}
+		*field = n
+	}
+
+	return nil
+}
+
+func (c *Config) validate() error {
+	switch {
+	case c.Refresh.Interval <= 0:
+		return errors.New("refresh.interval must be positive")
+	case c.Retention.Feed <= 0:
+		return errors.New("retention.feed must be positive")
+	case c.Retention.Summary <= 0:
+		return errors.New("retention.summary must be positive")
+	case c.HTTP.Timeout <= 0:
+		return errors.New("http.timeout must be positive")
+	case c.Extractor.Delay < 0:
+		return errors.New("extractor.delay must not be negative")
+	case c.Extractor.Workers < 1:
+		return errors.New("extractor.workers must be at least 1")
+	case c.Extractor.Retries < 0:
+		return errors.New("extractor.retries must not be negative")
+	case c.Extractor.MaxPages < 1:
+		return errors.New("extractor.max_pages must be at least 1")
+	case c.Archive.MaxSize < 1:
+		return errors.New("archive.max_size must be at least 1")
+	case !slices.Contains([]string{"openai", "openai-compatible", "anthropic", "ollama"}, c.LLM.Provider):
+		return fmt.Errorf("unknown llm.provider %q", c.LLM.Provider)
+	case c.LLM.Provider == "openai-compatible" && c.LLM.BaseURL == "":
+		return errors.New("llm.base_url is required for openai-compatible")
+	case c.LLM.SmallModel == "" || c.LLM.LargeModel == "":
+		return errors.New("llm models must not be empty")
+	case c.LLM.Retries < 0:
+		return errors.New("llm.retries must not be negative")
+	case c.LLM.MonthlyBudget < 0:
+		return errors.New("llm.monthly_budget must not be negative")
+	case c.UI.ListHeight < 3:
+		return errors.New("ui.list_height must be at least 3")
+	case c.Summary.Template == "":
+		return errors.New("summary.template must not be empty")
+	case c.Summary.Budget < 1000:
+		return errors.New("summary.budget must be at least 1000")
+	}
+	return nil
+}
+
+// 擷取規則檔路徑
+func RulesPath() string {
+	if p := Get().Extractor.Rules; p != "" {
+		return p
+	}
+	if f := File(); f != "" {
+		return filepath.Join(filepath.Dir(f), "rules.toml")
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "rss-reader", "rules.toml")
+}
+
+// 概要範本目錄，位於設定檔同目錄的 prompts
+func PromptsDir() string {
+	if f := File(); f != "" {
+		return filepath.Join(filepath.Dir(f), "prompts")
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "rss-reader", "prompts")
+}
+
+// 以小時表示的保留範圍，供資料庫查詢使用
+func Hours(d time.Duration) int {
+	h := int(d / time.Hour)
+	if h < 1 {
+		return 1
+	}
+	return h
+}
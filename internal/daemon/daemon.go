@@ -0,0 +1,164 @@
+package daemon
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"log/slog"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"rss-reader/internal/config"
+	"rss-reader/internal/database"
+	"rss-reader/internal/util"
+)
+
+type Daemon struct {
+	database   *database.SQLite
+	updater    *util.Updater
+	summarizer *util.Summarizer
+	interval   time.Duration
+	logger     *slog.Logger
+	hup        chan os.Signal
+}
+
+// 未指定 --interval 時跟隨設定檔
+func (d *Daemon) getInterval() time.Duration {
+	if d.interval > 0 {
+		return d.interval
+	}
+	return config.Get().Refresh.Interval
+}
+
+// 不開啟介面，依排程抓取訂閱源、擷取內容並產生概要，收到 SIGINT/SIGTERM 時結束
+func Run(args []string) error {
+	fs := flag.NewFlagSet("serve", flag.ContinueOnError)
+	interval := fs.Duration("interval", 0, "collection interval (default: refresh.interval from config)")
+	logPath := fs.String("log", "", "log file path (default: rss-reader.log next to the database)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *interval < 0 {
+		return fmt.Errorf("invalid interval: %s", *interval)
+	}
+
+	db, err := database.NewSQLite()
+	if err != nil {
+		return fmt.Errorf("failed to init SQLite: %w", err)
+	}
+	defer db.Close()
+
+	lock, err := util.AcquireLock(util.LockPath(db.Path()))
+	if err != nil {
+		return err
+	}
+	defer lock.Release()
+
+	if *logPath == "" {
+		*logPath = filepath.Join(filepath.Dir(db.Path()), "rss-reader.log")
+	}
+	logFile, err := os.OpenFile(*logPath, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open log %s: %w", *logPath, err)
+	}
+	defer logFile.Close()
+
+	// 套用為預設 logger，util 內的 log.Printf 也會以 JSON 寫入同一檔案
+	logger := slog.New(slog.NewJSONHandler(logFile, nil))
+	slog.SetDefault(logger)
+	log.SetFlags(0)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// SIGHUP 重新載入設定檔
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	defer signal.Stop(hup)
+
+	collector := util.NewCollector(db)
+	extractor := util.NewExtractor()
+	d := &Daemon{
+		database:   db,
+		updater:    util.NewUpdater(db, collector, extractor),
+		summarizer: util.NewSummarizer(db),
+		interval:   *interval,
+		logger:     logger,
+		hup:        hup,
+	}
+
+	logger.Info("daemon started", "pid", os.Getpid(), "db", db.Path(), "config", config.File(), "interval", d.getInterval().String())
+	d.loop(ctx)
+	logger.Info("daemon stopped")
+	return nil
+}
+
+func (d *Daemon) loop(ctx context.Context) {
+	ticker := time.NewTicker(d.getInterval())
+	defer ticker.Stop()
+
+	for {
+		d.collect(ctx)
+		if !d.wait(ctx, ticker) {
+			return
+		}
+	}
+}
+
+// 等待下一次排程，期間處理 SIGHUP；結束時回傳 false
+func (d *Daemon) wait(ctx context.Context, ticker *time.Ticker) bool {
+	for {
+		select {
+		case <-ctx.Done():
+			return false
+		case <-d.hup:
+			if err := config.Reload(); err != nil {
+				d.logger.Error("failed to reload config", "error", err)
+				continue
+			}
+			ticker.Reset(d.getInterval())
+			d.logger.Info("config reloaded", "interval", d.getInterval().String())
+		case <-ticker.C:
+			return true
+		}
+	}
+}
+
+func (d *Daemon) collect(ctx context.Context) {
+	start := time.Now()
+
+	articles, newCount, err := d.updater.Check(ctx)
+	if err != nil {
+		if !errors.Is(err, context.Canceled) {
+			d.logger.Error("failed to get news list", "error", err)
+		}
+		return
+	}
+	d.logger.Info("feeds checked", "total", len(articles), "new", newCount)
+
+	if newCount == 0 {
+		return
+	}
+
+	err = d.updater.Load(ctx, articles, nil)
+	if err != nil {
+		if !errors.Is(err, context.Canceled) {
+			d.logger.Error("failed to load content", "error", err)
+		}
+		return
+	}
+	d.logger.Info("content loaded", "duration", time.Since(start).String())
+
+	if _, err := d.summarizer.Generate(ctx, articles, nil, nil); err != nil {
+		if !errors.Is(err, context.Canceled) {
+			d.logger.Error("failed to generate summary", "error", err)
+		}
+		return
+	}
+	d.logger.Info("summary generated")
+}
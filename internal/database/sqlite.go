@@ -1,24 +1,51 @@
 package database
 
 import (
+	"context"
 	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
+	"sync/atomic"
+	"time"
 
+	"rss-reader/internal/config"
 	"rss-reader/internal/model"
 
 	_ "github.com/mattn/go-sqlite3"
 )
 
+// daemon 持有寫入鎖時，TUI 的寫入一律回傳此錯誤
+var ErrReadOnly = errors.New("database is read-only while the daemon is running")
+
 type SQLite struct {
-	db *sql.DB
+	db             *sql.DB
+	path           string
+	readOnly       atomic.Bool
+	insertStmt     *sql.Stmt
+	getStmt        *sql.Stmt
+	getFromURLStmt *sql.Stmt
+}
+
+// 批次寫入用的資料列，Content 為 nil 時僅寫入 RSS 資訊
+type Entry struct {
+	News    model.News
+	Content *model.NewsContent
+}
+
+// 等待擷取的文章，Attempts 為已失敗的次數
+type QueueItem struct {
+	News     model.News
+	Attempts int
 }
 
 func NewSQLite() (*SQLite, error) {
 	var dbPath string
 
-	if customPath := os.Getenv("RSS_DB_PATH"); customPath != "" {
+	if customPath := config.Get().Database.Path; customPath != "" {
 		dbPath = customPath
 	} else {
 		if isDev() {
@@ -50,19 +77,47 @@ func NewSQLite() (*SQLite, error) {
 		}
 	}
 
-	db, err := sql.Open("sqlite3", dbPath)
+	// WAL 允許讀寫並行，busy_timeout 避免並行寫入時直接回傳 database is locked
+	dsn := fmt.Sprintf("%s?_journal_mode=WAL&_busy_timeout=5000&_synchronous=NORMAL&_txlock=immediate", dbPath)
+	db, err := sql.Open("sqlite3", dsn)
 	if err != nil {
 		return nil, err
 	}
 
-	s := &SQLite{db: db}
+	s := &SQLite{db: db, path: dbPath}
 	if err := s.create(); err != nil {
+		db.Close()
+		return nil, err
+	}
+	if err := s.prepare(); err != nil {
+		db.Close()
 		return nil, err
 	}
 
 	return s, nil
 }
 
+// 將資料庫設為唯讀，之後的寫入皆回傳 ErrReadOnly
+func (s *SQLite) SetReadOnly() {
+	s.readOnly.Store(true)
+}
+
+func (s *SQLite) ReadOnly() bool {
+	return s.readOnly.Load()
+}
+
+func (s *SQLite) writable() error {
+	if s.readOnly.Load() {
+		return ErrReadOnly
+	}
+	return nil
+}
+
+// 資料庫檔案路徑，鎖定檔與日誌預設放在同一目錄
+func (s *SQLite) Path() string {
+	return s.path
+}
+
 func isDev() bool {
 	if _, err := os.Stat("go.mod"); err == nil {
 		return true
@@ -109,21 +164,133 @@ func (s *SQLite) create() error {
         value TEXT
     );
 
+		CREATE TABLE IF NOT EXISTS archives (
+        url TEXT PRIMARY KEY,
+        hash TEXT NOT NULL,
+        size INTEGER,
+        created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+    );
+
+		CREATE TABLE IF NOT EXISTS queue (
+        url TEXT PRIMARY KEY,
+        title TEXT NOT NULL,
+        content TEXT,
+        source TEXT,
+        published_at DATETIME,
+        no_pub_date INTEGER DEFAULT 0,
+        attempts INTEGER DEFAULT 0,
+        last_error TEXT,
+        created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+        updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+    );
+
+		CREATE TABLE IF NOT EXISTS translations (
+        url TEXT NOT NULL,
+        language TEXT NOT NULL,
+        content TEXT NOT NULL,
+        created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+        PRIMARY KEY (url, language)
+    );
+
+		CREATE TABLE IF NOT EXISTS usage (
+        id INTEGER PRIMARY KEY AUTOINCREMENT,
+        provider TEXT,
+        model TEXT NOT NULL,
+        purpose TEXT,
+        prompt_tokens INTEGER DEFAULT 0,
+        completion_tokens INTEGER DEFAULT 0,
+        created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+    );
+
+		CREATE TABLE IF NOT EXISTS summaries (
+        id INTEGER PRIMARY KEY AUTOINCREMENT,
+        content TEXT NOT NULL,
+        model TEXT,
+        articles TEXT,
+        created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+    );
+
     CREATE INDEX IF NOT EXISTS idx_news_url ON news(url);
     CREATE INDEX IF NOT EXISTS idx_news_published_at ON news(published_at);
     CREATE INDEX IF NOT EXISTS idx_news_source ON news(source);
     CREATE INDEX IF NOT EXISTS idx_feeds_url ON feeds(url);
     CREATE INDEX IF NOT EXISTS idx_feeds_dismiss ON feeds(dismiss);
     CREATE INDEX IF NOT EXISTS idx_data_key ON data(key);
+    CREATE INDEX IF NOT EXISTS idx_usage_created_at ON usage(created_at);
     `
 
-	_, err := s.db.Exec(query)
-	return err
+	if _, err := s.db.Exec(query); err != nil {
+		return err
+	}
+	return s.migrate()
 }
 
-func (s *SQLite) Insert(news model.News, content *model.NewsContent) error {
-	query := `
-	INSERT OR REPLACE INTO news (
+// 舊版資料庫缺少的欄位，依序以 ALTER TABLE 補上
+var columns = []struct {
+	table      string
+	name       string
+	definition string
+}{
+	{"news", "image", "TEXT"},
+	{"news", "section", "TEXT"},
+	{"news", "keywords", "TEXT"},
+	{"news", "language", "TEXT"},
+	{"news", "status", "TEXT"},
+	{"news", "http_status", "INTEGER"},
+	{"news", "confidence", "REAL"},
+	{"news", "strategy", "TEXT"},
+	{"news", "reading_time", "INTEGER"},
+	{"news", "summary", "TEXT"},
+	{"queue", "encoded", "TEXT"},
+}
+
+func (s *SQLite) migrate() error {
+	existing := make(map[string]bool)
+	loaded := make(map[string]bool)
+
+	for _, c := range columns {
+		if !loaded[c.table] {
+			if err := s.tableColumns(c.table, existing); err != nil {
+				return err
+			}
+			loaded[c.table] = true
+		}
+
+		key := c.table + "." + c.name
+		if existing[key] {
+			continue
+		}
+
+		query := fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s", c.table, c.name, c.definition)
+		if _, err := s.db.Exec(query); err != nil {
+			return err
+		}
+		existing[key] = true
+	}
+	return nil
+}
+
+func (s *SQLite) tableColumns(table string, existing map[string]bool) error {
+	rows, err := s.db.Query(fmt.Sprintf("PRAGMA table_info(%s)", table))
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var cid, notNull, pk int
+		var name, kind string
+		var value sql.NullString
+		if err := rows.Scan(&cid, &name, &kind, &notNull, &value, &pk); err != nil {
+			return err
+		}
+		existing[table+"."+name] = true
+	}
+	return rows.Err()
+}
+
+const insertQuery = `
+	INSERT INTO news (
 		title, 
 		url, 
 		content, 
@@ -131,9 +298,18 @@ func (s *SQLite) Insert(news model.News, content *model.NewsContent) error {
 		source, 
 		author, 
 		word_count, 
-		published_at
+		published_at,
+		image,
+		section,
+		keywords,
+		language,
+		status,
+		http_status,
+		confidence,
+		strategy,
+		reading_time
 	)
-  VALUES (
+	VALUES (
 		?, 
 		?, 
 		?, 
@@ -141,122 +317,398 @@ func (s *SQLite) Insert(news model.News, content *model.NewsContent) error {
 		?, 
 		?, 
 		?, 
+		?,
+		?,
+		?,
+		?,
+		?,
+		?,
+		?,
+		?,
+		?,
 		?
-	)`
+	)
+	ON CONFLICT(url) DO UPDATE SET
+		title = excluded.title,
+		content = excluded.content,
+		full_content = excluded.full_content,
+		source = excluded.source,
+		author = excluded.author,
+		word_count = excluded.word_count,
+		published_at = excluded.published_at,
+		image = excluded.image,
+		section = excluded.section,
+		keywords = excluded.keywords,
+		language = excluded.language,
+		status = excluded.status,
+		http_status = excluded.http_status,
+		confidence = excluded.confidence,
+		strategy = excluded.strategy,
+		reading_time = excluded.reading_time`
 
-	fullContent := ""
-	author := ""
-	wordCount := 0
+const selectQuery = `
+	SELECT 
+		title, 
+		url, 
+		COALESCE(content, ''), 
+		COALESCE(full_content, ''), 
+		COALESCE(source, ''), 
+		COALESCE(author, ''), 
+		COALESCE(word_count, 0), 
+		published_at,
+		COALESCE(image, ''),
+		COALESCE(section, ''),
+		COALESCE(keywords, ''),
+		COALESCE(language, ''),
+		COALESCE(status, ''),
+		COALESCE(http_status, 0),
+		COALESCE(confidence, 0),
+		COALESCE(strategy, ''),
+		COALESCE(reading_time, 0),
+		COALESCE(summary, '')
+	FROM news`
 
-	if content != nil {
-		fullContent = strings.TrimSpace(content.Content)
-		author = strings.TrimSpace(content.Author)
-		wordCount = content.WordCount
+func (s *SQLite) prepare() error {
+	var err error
+
+	if s.insertStmt, err = s.db.Prepare(insertQuery); err != nil {
+		return err
 	}
 
-	_, err := s.db.Exec(query,
-		strings.TrimSpace(news.Title),
-		strings.TrimSpace(news.URL),
-		strings.TrimSpace(news.Content),
-		fullContent,
-		strings.TrimSpace(news.Source),
-		author,
-		wordCount,
-		news.PublishedAt,
-	)
+	if s.getStmt, err = s.db.Prepare(selectQuery + `
+	WHERE published_at >= datetime('now', '-' || ? || ' hours')
+	ORDER BY published_at DESC`); err != nil {
+		return err
+	}
+
+	if s.getFromURLStmt, err = s.db.Prepare(selectQuery + `
+	WHERE url = ?`); err != nil {
+		return err
+	}
+
+	return nil
+}
 
+func (s *SQLite) Insert(ctx context.Context, news model.News, content *model.NewsContent) error {
+	if err := s.writable(); err != nil {
+		return err
+	}
+	_, err := s.insertStmt.ExecContext(ctx, insertArgs(news, content)...)
 	return err
 }
 
-func (s *SQLite) Get(hours int) ([]model.News, error) {
+// 以單一交易寫入多篇文章並移出擷取佇列，任一筆失敗則全部回滾
+func (s *SQLite) InsertBatch(ctx context.Context, list []Entry) error {
+	if err := s.writable(); err != nil {
+		return err
+	}
+	if len(list) == 0 {
+		return nil
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	stmt := tx.StmtContext(ctx, s.insertStmt)
+	defer stmt.Close()
+
+	dequeue, err := tx.PrepareContext(ctx, `DELETE FROM queue WHERE url = ?`)
+	if err != nil {
+		return err
+	}
+	defer dequeue.Close()
+
+	for _, e := range list {
+		if _, err := stmt.ExecContext(ctx, insertArgs(e.News, e.Content)...); err != nil {
+			return err
+		}
+		if _, err := dequeue.ExecContext(ctx, strings.TrimSpace(e.News.URL)); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// 將文章加入擷取佇列，已在佇列中的文章保留原本的重試次數
+func (s *SQLite) Enqueue(ctx context.Context, list []model.News) error {
+	if err := s.writable(); err != nil {
+		return err
+	}
+	if len(list) == 0 {
+		return nil
+	}
+
 	query := `
-	SELECT title, url, content, full_content, source, author, word_count, published_at
-	FROM news 
-	WHERE published_at >= datetime('now', '-' || ? || ' hours')
-	ORDER BY published_at DESC`
+	INSERT INTO queue (
+		url, 
+		title, 
+		content, 
+		source, 
+		published_at, 
+		no_pub_date, 
+		encoded
+	)
+	VALUES (
+		?, 
+		?, 
+		?, 
+		?, 
+		?, 
+		?, 
+		?
+	)
+	ON CONFLICT(url) DO NOTHING`
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
 
-	result, err := s.db.Query(query, hours)
+	stmt, err := tx.PrepareContext(ctx, query)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	for _, e := range list {
+		_, err := stmt.ExecContext(ctx,
+			strings.TrimSpace(e.URL),
+			strings.TrimSpace(e.Title),
+			strings.TrimSpace(e.Content),
+			strings.TrimSpace(e.Source),
+			e.PublishedAt,
+			e.NoPubDate,
+			e.Encoded,
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// 佇列中尚未完成的文章，依加入順序排列
+func (s *SQLite) Queue(ctx context.Context) ([]QueueItem, error) {
+	query := `
+	SELECT 
+		url, 
+		title, 
+		COALESCE(content, ''), 
+		COALESCE(source, ''), 
+		published_at, 
+		no_pub_date, 
+		attempts, 
+		COALESCE(encoded, '')
+	FROM queue 
+	ORDER BY created_at ASC`
+
+	result, err := s.db.QueryContext(ctx, query)
 	if err != nil {
 		return nil, err
 	}
 	defer result.Close()
 
-	var arr []model.News
-
+	var arr []QueueItem
 	for result.Next() {
-		var article model.News
-		var fullContent, author string
-		var wordCount int
-
+		var item QueueItem
 		err := result.Scan(
-			&article.Title,
-			&article.URL,
-			&article.Content,
-			&fullContent,
-			&article.Source,
-			&author,
-			&wordCount,
-			&article.PublishedAt,
+			&item.News.URL,
+			&item.News.Title,
+			&item.News.Content,
+			&item.News.Source,
+			&item.News.PublishedAt,
+			&item.News.NoPubDate,
+			&item.Attempts,
+			&item.News.Encoded,
 		)
-
 		if err != nil {
 			continue
 		}
-
-		if fullContent != "" {
-			article.FullContent = &fullContent
-		}
-		if author != "" {
-			article.Author = &author
-		}
-		if wordCount > 0 {
-			article.WordCount = &wordCount
-		}
-
-		arr = append(arr, article)
+		arr = append(arr, item)
 	}
 
-	return arr, nil
+	return arr, result.Err()
 }
 
-func (s *SQLite) GetFromURL(url string) (*model.News, error) {
+// 記錄擷取失敗次數，重新啟動後延續退避
+func (s *SQLite) UpdateQueue(ctx context.Context, url string, attempts int, lastError string) error {
+	if err := s.writable(); err != nil {
+		return err
+	}
 	query := `
-	SELECT title, url, content, full_content, source, author, word_count, published_at 
-	FROM news 
+	UPDATE queue 
+	SET 
+		attempts = ?, 
+		last_error = ?, 
+		updated_at = CURRENT_TIMESTAMP
 	WHERE url = ?`
 
-	var stored model.News
-	var fullContent, author string
-	var wordCount int
+	_, err := s.db.ExecContext(ctx, query, attempts, lastError, strings.TrimSpace(url))
+	return err
+}
+
+func insertArgs(news model.News, content *model.NewsContent) []any {
+	fullContent := ""
+	author := ""
+	wordCount := 0
+	image := ""
+	section := ""
+	keywords := ""
+	language := ""
+	status := ""
+	httpStatus := 0
+	confidence := 0.0
+	strategy := ""
+	readingTime := 0
+
+	if content != nil {
+		fullContent = strings.TrimSpace(content.Content)
+		author = strings.TrimSpace(content.Author)
+		wordCount = content.WordCount
+		image = strings.TrimSpace(content.Image)
+		section = strings.TrimSpace(content.Section)
+		keywords = strings.Join(content.Keywords, ",")
+		language = strings.TrimSpace(content.Language)
+		status = content.Status
+		httpStatus = content.HTTPStatus
+		confidence = content.Confidence
+		strategy = content.Strategy
+		readingTime = content.ReadingTime
+	}
+
+	return []any{
+		strings.TrimSpace(news.Title),
+		strings.TrimSpace(news.URL),
+		strings.TrimSpace(news.Content),
+		fullContent,
+		strings.TrimSpace(news.Source),
+		author,
+		wordCount,
+		news.PublishedAt,
+		image,
+		section,
+		keywords,
+		language,
+		status,
+		httpStatus,
+		confidence,
+		strategy,
+		readingTime,
+	}
+}
+
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+func scanNews(row scanner) (*model.News, error) {
+	var article model.News
+	var fullContent, author, image, section, keywords, language, summary string
+	var wordCount, readingTime int
 
-	err := s.db.QueryRow(query, url).Scan(
-		&stored.Title,
-		&stored.URL,
-		&stored.Content,
+	err := row.Scan(
+		&article.Title,
+		&article.URL,
+		&article.Content,
 		&fullContent,
-		&stored.Source,
+		&article.Source,
 		&author,
 		&wordCount,
-		&stored.PublishedAt,
+		&article.PublishedAt,
+		&image,
+		&section,
+		&keywords,
+		&language,
+		&article.Status,
+		&article.HTTPStatus,
+		&article.Confidence,
+		&article.Strategy,
+		&readingTime,
+		&summary,
 	)
-
 	if err != nil {
 		return nil, err
 	}
+
 	if fullContent != "" {
-		stored.FullContent = &fullContent
+		article.FullContent = &fullContent
 	}
 	if author != "" {
-		stored.Author = &author
+		article.Author = &author
 	}
 	if wordCount > 0 {
-		stored.WordCount = &wordCount
+		article.WordCount = &wordCount
+	}
+	if readingTime > 0 {
+		article.ReadingTime = &readingTime
+	}
+	if summary != "" {
+		article.Summary = &summary
+	}
+	if image != "" {
+		article.Image = &image
+	}
+	if section != "" {
+		article.Section = &section
+	}
+	if keywords != "" {
+		article.Keywords = strings.Split(keywords, ",")
+	}
+	if language != "" {
+		article.Language = &language
 	}
 
-	return &stored, nil
+	return &article, nil
 }
 
-func (s *SQLite) InsertFeed(url string) error {
+func (s *SQLite) Get(ctx context.Context, hours int) ([]model.News, error) {
+	result, err := s.getStmt.QueryContext(ctx, hours)
+	if err != nil {
+		return nil, err
+	}
+	defer result.Close()
+
+	var arr []model.News
+
+	for result.Next() {
+		article, err := scanNews(result)
+		if err != nil {
+			continue
+		}
+		arr = append(arr, *article)
+	}
+
+	return arr, nil
+}
+
+func (s *SQLite) GetFromURL(ctx context.Context, url string) (*model.News, error) {
+	return scanNews(s.getFromURLStmt.QueryRowContext(ctx, url))
+}
+
+// 儲存單篇文章的概要，重新擷取內容時保留
+func (s *SQLite) SetSummary(ctx context.Context, url, summary string) error {
+	if err := s.writable(); err != nil {
+		return err
+	}
+	query := `
+	UPDATE news 
+	SET summary = ?
+	WHERE url = ?`
+
+	_, err := s.db.ExecContext(ctx, query, strings.TrimSpace(summary), strings.TrimSpace(url))
+	return err
+}
+
+func (s *SQLite) InsertFeed(ctx context.Context, url string) error {
+	if err := s.writable(); err != nil {
+		return err
+	}
 	query := `
 	INSERT OR REPLACE INTO feeds (
 		url, 
@@ -269,11 +721,63 @@ func (s *SQLite) InsertFeed(url string) error {
 		CURRENT_TIMESTAMP
 	)`
 
-	_, err := s.db.Exec(query, strings.TrimSpace(url))
+	_, err := s.db.ExecContext(ctx, query, strings.TrimSpace(url))
 	return err
 }
 
-func (s *SQLite) RemoveFeed(url string) error {
+// 以單一交易新增多個訂閱源
+func (s *SQLite) InsertFeeds(ctx context.Context, urls []string) error {
+	if err := s.writable(); err != nil {
+		return err
+	}
+	if len(urls) == 0 {
+		return nil
+	}
+
+	query := `
+	INSERT INTO feeds (
+		url, 
+		dismiss, 
+		updated_at
+	)
+	VALUES (
+		?, 
+		0,
+		CURRENT_TIMESTAMP
+	)
+	ON CONFLICT(url) DO UPDATE SET
+		dismiss = 0,
+		updated_at = CURRENT_TIMESTAMP`
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.PrepareContext(ctx, query)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	for _, url := range urls {
+		url = strings.TrimSpace(url)
+		if url == "" {
+			continue
+		}
+		if _, err := stmt.ExecContext(ctx, url); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (s *SQLite) RemoveFeed(ctx context.Context, url string) error {
+	if err := s.writable(); err != nil {
+		return err
+	}
 	query := `
 	UPDATE feeds 
 	SET 
@@ -281,18 +785,18 @@ func (s *SQLite) RemoveFeed(url string) error {
 		updated_at = CURRENT_TIMESTAMP
 	WHERE url = ?`
 
-	_, err := s.db.Exec(query, strings.TrimSpace(url))
+	_, err := s.db.ExecContext(ctx, query, strings.TrimSpace(url))
 	return err
 }
 
-func (s *SQLite) GetFeed() ([]string, error) {
+func (s *SQLite) GetFeed(ctx context.Context) ([]string, error) {
 	query := `
 	SELECT url
 	FROM feeds 
 	WHERE dismiss = 0 
 	ORDER BY created_at ASC`
 
-	result, err := s.db.Query(query)
+	result, err := s.db.QueryContext(ctx, query)
 	if err != nil {
 		return nil, err
 	}
@@ -310,35 +814,278 @@ func (s *SQLite) GetFeed() ([]string, error) {
 	return feeds, nil
 }
 
-func (s *SQLite) GetKey(key string) (string, error) {
+func (s *SQLite) GetKey(ctx context.Context, key string) (string, error) {
 	query := `
 	SELECT value
 	FROM data 
 	WHERE key = ?`
 
 	var value string
-	err := s.db.QueryRow(query, key).Scan(&value)
+	err := s.db.QueryRowContext(ctx, query, key).Scan(&value)
 	if err != nil {
 		return "", err
 	}
 	return value, nil
 }
 
-func (s *SQLite) SetKey(key, value string) error {
+func (s *SQLite) SetKey(ctx context.Context, key, value string) error {
+	if err := s.writable(); err != nil {
+		return err
+	}
+	query := `
+	INSERT OR REPLACE INTO data (
+		key, 
+		value
+	)
+	VALUES (
+		?, 
+		?
+	)`
+
+	_, err := s.db.ExecContext(ctx, query, strings.TrimSpace(key), strings.TrimSpace(value))
+	return err
+}
+
+// 記錄文章的離線快照，hash 為快照 HTML 在封存目錄中的雜湊
+func (s *SQLite) SetArchive(ctx context.Context, url, hash string, size int64) error {
+	if err := s.writable(); err != nil {
+		return err
+	}
+	query := `
+	INSERT INTO archives (
+		url, 
+		hash, 
+		size
+	)
+	VALUES (
+		?, 
+		?, 
+		?
+	)
+	ON CONFLICT(url) DO UPDATE SET
+		hash = excluded.hash,
+		size = excluded.size,
+		created_at = CURRENT_TIMESTAMP`
+
+	_, err := s.db.ExecContext(ctx, query, strings.TrimSpace(url), hash, size)
+	return err
+}
+
+func (s *SQLite) GetArchive(ctx context.Context, url string) (string, error) {
+	query := `
+	SELECT hash
+	FROM archives 
+	WHERE url = ?`
+
+	var hash string
+	err := s.db.QueryRowContext(ctx, query, strings.TrimSpace(url)).Scan(&hash)
+	if err != nil {
+		return "", err
+	}
+	return hash, nil
+}
+
+func (s *SQLite) GetTranslation(ctx context.Context, url, language string) (string, error) {
 	query := `
+	SELECT content
+	FROM translations 
+	WHERE url = ? AND language = ?`
+
+	var content string
+	err := s.db.QueryRowContext(ctx, query, strings.TrimSpace(url), language).Scan(&content)
+	if err != nil {
+		return "", err
+	}
+	return content, nil
+}
+
+func (s *SQLite) SetTranslation(ctx context.Context, url, language, content string) error {
+	if err := s.writable(); err != nil {
+		return err
+	}
+	query := `
+	INSERT INTO translations (
+		url, 
+		language, 
+		content
+	)
+	VALUES (
+		?, 
+		?, 
+		?
+	)
+	ON CONFLICT(url, language) DO UPDATE SET
+		content = excluded.content,
+		created_at = CURRENT_TIMESTAMP`
+
+	_, err := s.db.ExecContext(ctx, query, strings.TrimSpace(url), language, strings.TrimSpace(content))
+	return err
+}
+
+// 產生過的概要，Articles 為納入的文章網址
+type Summary struct {
+	ID        int64     `json:"id"`
+	Content   string    `json:"content"`
+	Model     string    `json:"model"`
+	Articles  []string  `json:"articles"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// 保存概要並更新 data 表中的最新概要
+func (s *SQLite) AddSummary(ctx context.Context, summary Summary) error {
+	if err := s.writable(); err != nil {
+		return err
+	}
+	articles, err := json.Marshal(summary.Articles)
+	if err != nil {
+		return err
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `
+	INSERT INTO summaries (
+		content, 
+		model, 
+		articles
+	)
+	VALUES (
+		?, 
+		?, 
+		?
+	)`
+	if _, err := tx.ExecContext(ctx, query, summary.Content, summary.Model, string(articles)); err != nil {
+		return err
+	}
+
+	query = `
 	INSERT OR REPLACE INTO data (
 		key, 
 		value
 	)
 	VALUES (
+		'summary', 
+		?
+	)`
+	if _, err := tx.ExecContext(ctx, query, strings.TrimSpace(summary.Content)); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// 由新到舊列出最近 limit 份概要
+func (s *SQLite) Summaries(ctx context.Context, limit int) ([]Summary, error) {
+	query := `
+	SELECT id, content, model, articles, created_at
+	FROM summaries 
+	ORDER BY id DESC
+	LIMIT ?`
+
+	rows, err := s.db.QueryContext(ctx, query, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var list []Summary
+	for rows.Next() {
+		var summary Summary
+		var model, articles sql.NullString
+		if err := rows.Scan(&summary.ID, &summary.Content, &model, &articles, &summary.CreatedAt); err != nil {
+			return nil, err
+		}
+		summary.Model = model.String
+		if articles.Valid {
+			json.Unmarshal([]byte(articles.String), &summary.Articles)
+		}
+		list = append(list, summary)
+	}
+	return list, rows.Err()
+}
+
+// 一次 LLM 呼叫的 token 用量
+type Usage struct {
+	Provider         string
+	Model            string
+	Purpose          string
+	PromptTokens     int
+	CompletionTokens int
+}
+
+// 依期間與模型加總的用量
+type UsageTotal struct {
+	Period           string `json:"period"`
+	Model            string `json:"model"`
+	Calls            int    `json:"calls"`
+	PromptTokens     int    `json:"prompt_tokens"`
+	CompletionTokens int    `json:"completion_tokens"`
+}
+
+func (s *SQLite) AddUsage(ctx context.Context, usage Usage) error {
+	if err := s.writable(); err != nil {
+		return err
+	}
+	query := `
+	INSERT INTO usage (
+		provider, 
+		model, 
+		purpose, 
+		prompt_tokens, 
+		completion_tokens
+	)
+	VALUES (
+		?, 
+		?, 
+		?, 
 		?, 
 		?
 	)`
 
-	_, err := s.db.Exec(query, strings.TrimSpace(key), strings.TrimSpace(value))
+	_, err := s.db.ExecContext(ctx, query, usage.Provider, usage.Model, usage.Purpose, usage.PromptTokens, usage.CompletionTokens)
 	return err
 }
 
+// 加總 since 之後的用量，layout 為 strftime 格式（如 %Y-%m-%d 依日、%Y-%m 依月），期間以本地時間計
+func (s *SQLite) UsageTotals(ctx context.Context, layout string, since time.Time) ([]UsageTotal, error) {
+	query := `
+	SELECT 
+		strftime(?, created_at, 'localtime') AS period, 
+		model, 
+		COUNT(*), 
+		SUM(prompt_tokens), 
+		SUM(completion_tokens)
+	FROM usage 
+	WHERE created_at >= ?
+	GROUP BY period, model
+	ORDER BY period DESC, model`
+
+	rows, err := s.db.QueryContext(ctx, query, layout, since.UTC().Format("2006-01-02 15:04:05"))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var list []UsageTotal
+	for rows.Next() {
+		var total UsageTotal
+		if err := rows.Scan(&total.Period, &total.Model, &total.Calls, &total.PromptTokens, &total.CompletionTokens); err != nil {
+			return nil, err
+		}
+		list = append(list, total)
+	}
+	return list, rows.Err()
+}
+
 func (s *SQLite) Close() error {
+	for _, stmt := range []*sql.Stmt{s.insertStmt, s.getStmt, s.getFromURLStmt} {
+		if stmt != nil {
+			stmt.Close()
+		}
+	}
 	return s.db.Close()
 }
@@ -2,21 +2,163 @@ package model
 
 import "time"
 
+// 擷取狀態
+const (
+	StatusSuccess   = "success"
+	StatusEmpty     = "empty"
+	StatusPaywalled = "paywalled"
+	StatusConsent   = "consent"
+	StatusError     = "error"
+)
+
+// 正文來源
+const (
+	StrategyRule        = "rule"
+	StrategyReadability = "readability"
+	StrategyFeed        = "feed"
+	StrategyAMP         = "amp"
+	StrategyDescription = "description"
+)
+
 type News struct {
+	Title       string    `json:"title"`
+	Content     string    `json:"content"`
+	Source      string    `json:"source"`
+	URL         string    `json:"url"`
+	PublishedAt time.Time `json:"published_at"`
+
+	FullContent *string  `json:"full_content,omitempty"`
+	Author      *string  `json:"author,omitempty"`
+	WordCount   *int     `json:"word_count,omitempty"`
+	ReadingTime *int     `json:"reading_time,omitempty"`
+	Image       *string  `json:"image,omitempty"`
+	Section     *string  `json:"section,omitempty"`
+	Keywords    []string `json:"keywords,omitempty"`
+	Language    *string  `json:"language,omitempty"`
+	Summary     *string  `json:"summary,omitempty"`
+
+	Status     string  `json:"status,omitempty"`
+	HTTPStatus int     `json:"http_status,omitempty"`
+	Confidence float64 `json:"confidence,omitempty"`
+	Strategy   string  `json:"strategy,omitempty"`
+
+	// RSS 沒有提供發布時間，擷取後改用頁面上的發布時間
+	NoPubDate bool `json:"-"`
+	// RSS content:encoded 的 HTML，作為擷取失敗時的備援
+	Encoded string `json:"-"`
+}
+
+// 付費牆或 cookie 同意頁擋住正文，內容不可作為概要來源
+func (n News) IsBlocked() bool {
+	return n.Status == StatusPaywalled || n.Status == StatusConsent
+}
+
+// 閱讀分鐘數，未擷取時為 0
+func (n News) Minutes() int {
+	if n.ReadingTime == nil {
+		return 0
+	}
+	return *n.ReadingTime
+}
+
+// 是否已嘗試擷取，已擷取的文章不會在瀏覽時重新抓取
+func (n News) IsExtracted() bool {
+	return n.FullContent != nil || n.Status != ""
+}
+
+type NewsContent struct {
 	Title       string
+	Author      string
 	Content     string
-	Source      string
-	URL         string
+	WordCount   int
+	ReadingTime int
 	PublishedAt time.Time
+	Image       string
+	Section     string
+	Keywords    []string
+	Language    string
+	Summary     string
+	Status      string
+	HTTPStatus  int
+	// 0 到 1，正文長度與段落結構的信心分數
+	Confidence float64
+	Strategy   string
+	// 頁面提供的 AMP 版本網址
+	AMP string
+}
 
-	FullContent *string
-	Author      *string
-	WordCount   *int
+// 將資料庫中的文章轉回擷取結果
+func (n News) Extracted() *NewsContent {
+	content := &NewsContent{
+		Title:       n.Title,
+		PublishedAt: n.PublishedAt,
+		Keywords:    n.Keywords,
+		Status:      n.Status,
+		HTTPStatus:  n.HTTPStatus,
+		Confidence:  n.Confidence,
+		Strategy:    n.Strategy,
+	}
+	if n.FullContent != nil {
+		content.Content = *n.FullContent
+	}
+	if n.Author != nil {
+		content.Author = *n.Author
+	}
+	if n.WordCount != nil {
+		content.WordCount = *n.WordCount
+	}
+	if n.ReadingTime != nil {
+		content.ReadingTime = *n.ReadingTime
+	}
+	if n.Image != nil {
+		content.Image = *n.Image
+	}
+	if n.Section != nil {
+		content.Section = *n.Section
+	}
+	if n.Language != nil {
+		content.Language = *n.Language
+	}
+	if n.Summary != nil {
+		content.Summary = *n.Summary
+	}
+	return content
 }
 
-type NewsContent struct {
-	Title     string
-	Author    string
-	Content   string
-	WordCount int
+// 將擷取結果套用到文章，與 Extracted 相反；空值不覆蓋
+func (n *News) Apply(content *NewsContent) {
+	if content == nil {
+		return
+	}
+	if n.Title == "" {
+		n.Title = content.Title
+	}
+	if n.PublishedAt.IsZero() {
+		n.PublishedAt = content.PublishedAt
+	}
+	text := func(field **string, value string) {
+		if value != "" {
+			*field = &value
+		}
+	}
+	if content.Content != "" {
+		n.FullContent = &content.Content
+	}
+	if content.WordCount > 0 {
+		n.WordCount = &content.WordCount
+	}
+	if content.ReadingTime > 0 {
+		n.ReadingTime = &content.ReadingTime
+	}
+	text(&n.Author, content.Author)
+	text(&n.Image, content.Image)
+	text(&n.Section, content.Section)
+	text(&n.Language, content.Language)
+	if len(content.Keywords) > 0 {
+		n.Keywords = content.Keywords
+	}
+	n.Status = content.Status
+	n.HTTPStatus = content.HTTPStatus
+	n.Confidence = content.Confidence
+	n.Strategy = content.Strategy
 }
@@ -20,4 +20,6 @@ type Item struct {
 	Description string `xml:"description"`
 	Link        string `xml:"link"`
 	PubDate     string `xml:"pubDate"`
+	// 部分 RSS 以 content:encoded 提供全文 HTML
+	Encoded string `xml:"http://purl.org/rss/1.0/modules/content/ encoded"`
 }
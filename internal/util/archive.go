@@ -0,0 +1,261 @@
+package util
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"rss-reader/internal/config"
+	"rss-reader/internal/database"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// 離線快照以內容雜湊存放在 rss.db 同目錄的 archive/，
+// 快照 HTML 與圖片都是 archive/<前兩碼>/<sha256>.<副檔名>，相同圖片只存一份
+type Archiver struct {
+	db     *database.SQLite
+	client *http.Client
+	dir    string
+}
+
+func NewArchiver(db *database.SQLite) *Archiver {
+	return &Archiver{
+		db:     db,
+		client: &http.Client{},
+		dir:    filepath.Join(filepath.Dir(db.Path()), "archive"),
+	}
+}
+
+// 已封存文章的快照路徑
+func (a *Archiver) Path(ctx context.Context, pageURL string) (string, error) {
+	hash, err := a.db.GetArchive(ctx, pageURL)
+	if err != nil {
+		return "", err
+	}
+	p := a.objectPath(hash, ".html")
+	if _, err := os.Stat(p); err != nil {
+		return "", err
+	}
+	return p, nil
+}
+
+// 下載頁面與圖片並保存快照，回傳快照路徑
+func (a *Archiver) Archive(ctx context.Context, pageURL string) (string, error) {
+	return a.archive(ctx, pageURL, nil)
+}
+
+// limiter 不為 nil 時，頁面與圖片的請求都依網站排隊，與背景擷取共用請求間隔
+func (a *Archiver) archive(ctx context.Context, pageURL string, limiter *hostLimiter) (string, error) {
+	limit := int64(config.Get().Archive.MaxSize) << 20
+
+	body, _, err := a.download(ctx, limiter, pageURL, limit)
+	if err != nil {
+		return "", err
+	}
+
+	base, err := url.Parse(pageURL)
+	if err != nil {
+		return "", err
+	}
+	doc, err := goquery.NewDocumentFromReader(bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+
+	// 移除離線無法使用或會連回網路的內容
+	doc.Find("script, noscript, iframe, object, embed, link, meta[http-equiv='refresh'], picture source").Remove()
+	doc.Find("*").Each(func(i int, s *goquery.Selection) {
+		var handlers []string
+		for _, attr := range s.Nodes[0].Attr {
+			if strings.HasPrefix(strings.ToLower(attr.Key), "on") {
+				handlers = append(handlers, attr.Key)
+			}
+		}
+		for _, key := range handlers {
+			s.RemoveAttr(key)
+		}
+	})
+	doc.Find("a[href]").Each(func(i int, s *goquery.Selection) {
+		href, _ := s.Attr("href")
+		if u, err := base.Parse(strings.TrimSpace(href)); err == nil {
+			s.SetAttr("href", u.String())
+		}
+	})
+
+	size := int64(len(body))
+	images := make(map[string]string)
+	doc.Find("img").Each(func(i int, s *goquery.Selection) {
+		src := imageSource(s)
+		if src == "" {
+			return
+		}
+		u, err := base.Parse(src)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+			return
+		}
+
+		local, ok := images[u.String()]
+		if !ok {
+			data, contentType, err := a.download(ctx, limiter, u.String(), limit-size)
+			if err != nil || !strings.HasPrefix(contentType, "image/") {
+				// 超過大小上限或下載失敗時保留原網址
+				s.SetAttr("src", u.String())
+				return
+			}
+			hash, err := a.store(data, imageExt(contentType, u.Path))
+			if err != nil {
+				log.Printf("Failed to store image %s: %v", u, err)
+				return
+			}
+			size += int64(len(data))
+			// 快照與圖片都位於 archive 下一層目錄
+			local = "../" + hash[:2] + "/" + hash + imageExt(contentType, u.Path)
+			images[u.String()] = local
+		}
+
+		s.SetAttr("src", local)
+		for _, attr := range []string{"srcset", "sizes", "loading", "data-src", "data-original", "data-lazy-src", "data-srcset"} {
+			s.RemoveAttr(attr)
+		}
+	})
+
+	head := doc.Find("head")
+	head.AppendHtml(fmt.Sprintf(`<meta name="rss-reader-source" content="%s">`, htmlAttr(pageURL)))
+	head.AppendHtml(fmt.Sprintf(`<meta name="rss-reader-archived" content="%s">`, time.Now().Format(time.RFC3339)))
+	head.AppendHtml(`<style>body{max-width:48em;margin:0 auto;padding:1em;line-height:1.6}img{max-width:100%;height:auto}</style>`)
+
+	snapshot, err := doc.Html()
+	if err != nil {
+		return "", err
+	}
+	hash, err := a.store([]byte(snapshot), ".html")
+	if err != nil {
+		return "", err
+	}
+
+	if err := a.db.SetArchive(ctx, pageURL, hash, size); err != nil {
+		return "", err
+	}
+	return a.objectPath(hash, ".html"), nil
+}
+
+// 下載內容，超過 limit 位元組時回傳錯誤
+func (a *Archiver) download(ctx context.Context, limiter *hostLimiter, link string, limit int64) ([]byte, string, error) {
+	if limit <= 0 {
+		return nil, "", errors.New("archive size limit reached")
+	}
+	if limiter != nil {
+		if err := limiter.wait(ctx, hostname(link)); err != nil {
+			return nil, "", err
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, config.Get().HTTP.Timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, link, nil)
+	if err != nil {
+		return nil, "", err
+	}
+
+	res, err := a.client.Do(req)
+	if err != nil {
+		return nil, "", err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, "", fmt.Errorf("unexpected status %s", res.Status)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(res.Body, limit+1))
+	if err != nil {
+		return nil, "", err
+	}
+	if int64(len(data)) > limit {
+		return nil, "", fmt.Errorf("%s exceeds archive size limit", link)
+	}
+
+	contentType, _, _ := mime.ParseMediaType(res.Header.Get("Content-Type"))
+	return data, contentType, nil
+}
+
+// 以 sha256 寫入封存目錄，已存在時略過
+func (a *Archiver) store(data []byte, ext string) (string, error) {
+	sum := sha256.Sum256(data)
+	hash := hex.EncodeToString(sum[:])
+	p := a.objectPath(hash, ext)
+
+	if _, err := os.Stat(p); err == nil {
+		return hash, nil
+	}
+	if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+		return "", err
+	}
+
+	// 先寫入暫存檔再改名，避免中斷時留下不完整的檔案
+	tmp := p + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return "", err
+	}
+	return hash, os.Rename(tmp, p)
+}
+
+func (a *Archiver) objectPath(hash, ext string) string {
+	return filepath.Join(a.dir, hash[:2], hash+ext)
+}
+
+// 延遲載入的圖片常把真正的網址放在 data-* 或 srcset
+func imageSource(s *goquery.Selection) string {
+	for _, attr := range []string{"data-src", "data-original", "data-lazy-src", "src"} {
+		if v, ok := s.Attr(attr); ok && strings.TrimSpace(v) != "" && !strings.HasPrefix(v, "data:") {
+			return strings.TrimSpace(v)
+		}
+	}
+	for _, attr := range []string{"srcset", "data-srcset"} {
+		if v, ok := s.Attr(attr); ok {
+			if fields := strings.Fields(strings.Split(v, ",")[0]); len(fields) > 0 {
+				return fields[0]
+			}
+		}
+	}
+	return ""
+}
+
+func imageExt(contentType, urlPath string) string {
+	switch contentType {
+	case "image/jpeg":
+		return ".jpg"
+	case "image/png":
+		return ".png"
+	case "image/gif":
+		return ".gif"
+	case "image/webp":
+		return ".webp"
+	case "image/svg+xml":
+		return ".svg"
+	case "image/avif":
+		return ".avif"
+	}
+	if ext := strings.ToLower(filepath.Ext(urlPath)); len(ext) > 1 && len(ext) <= 5 {
+		return ext
+	}
+	return ".img"
+}
+
+func htmlAttr(str string) string {
+	return strings.NewReplacer("&", "&amp;", `"`, "&quot;", "<", "&lt;", ">", "&gt;").Replace(str)
+}
@@ -1,6 +1,7 @@
 package util
 
 import (
+	"context"
 	"encoding/xml"
 	"html"
 	"io"
@@ -11,6 +12,7 @@ import (
 	"strings"
 	"time"
 
+	"rss-reader/internal/config"
 	"rss-reader/internal/database"
 	"rss-reader/internal/model"
 )
@@ -23,29 +25,28 @@ type Collector struct {
 func NewCollector(db *database.SQLite) *Collector {
 	return &Collector{
 		db: db,
-		client: &http.Client{
-			Timeout: 30 * time.Second,
-		},
+		// 逾時改由每次請求的 context 控制，重新載入設定後立即生效
+		client: &http.Client{},
 	}
 }
 
-func (c *Collector) Add(link string) error {
+func (c *Collector) Add(ctx context.Context, link string) error {
 	if link == "" {
 		return nil
 	}
-	return c.db.InsertFeed(link)
+	return c.db.InsertFeed(ctx, link)
 }
 
-func (c *Collector) List() ([]string, error) {
-	return c.db.GetFeed()
+func (c *Collector) List(ctx context.Context) ([]string, error) {
+	return c.db.GetFeed(ctx)
 }
 
-func (c *Collector) Remove(link string) error {
-	return c.db.RemoveFeed(link)
+func (c *Collector) Remove(ctx context.Context, link string) error {
+	return c.db.RemoveFeed(ctx, link)
 }
 
-func (c *Collector) GetNews() ([]model.News, error) {
-	feeds, err := c.db.GetFeed()
+func (c *Collector) GetNews(ctx context.Context) ([]model.News, error) {
+	feeds, err := c.db.GetFeed(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -53,10 +54,14 @@ func (c *Collector) GetNews() ([]model.News, error) {
 	var allArticles []model.News
 	URLMap := make(map[string]bool)
 	now := time.Now()
-	threeDay := now.Add(-72 * time.Hour)
+	threeDay := now.Add(-config.Get().Retention.Feed)
 
 	for _, feed := range feeds {
-		rss, err := c.fetch(feed)
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		rss, err := c.fetch(ctx, feed)
 		if err != nil {
 			log.Printf("Failed to get RSS %s: %v", feed, err)
 			continue
@@ -74,7 +79,7 @@ func (c *Collector) GetNews() ([]model.News, error) {
 			}
 			URLMap[item.Link] = true
 
-			publishedAt := c.parseDate(item.PubDate)
+			publishedAt, ok := c.parseDate(item.PubDate)
 			if publishedAt.Before(threeDay) {
 				continue
 			}
@@ -85,6 +90,8 @@ func (c *Collector) GetNews() ([]model.News, error) {
 				Source:      source,
 				URL:         item.Link,
 				PublishedAt: publishedAt,
+				NoPubDate:   !ok,
+				Encoded:     strings.TrimSpace(item.Encoded),
 			}
 			allArticles = append(allArticles, article)
 		}
@@ -97,8 +104,16 @@ func (c *Collector) GetNews() ([]model.News, error) {
 	return allArticles, nil
 }
 
-func (c *Collector) fetch(url string) (*model.RSS, error) {
-	resp, err := c.client.Get(url)
+func (c *Collector) fetch(ctx context.Context, url string) (*model.RSS, error) {
+	ctx, cancel := context.WithTimeout(ctx, config.Get().HTTP.Timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.client.Do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -117,9 +132,10 @@ func (c *Collector) fetch(url string) (*model.RSS, error) {
 	return &rss, nil
 }
 
-func (c *Collector) parseDate(str string) time.Time {
+// 無法解析時回傳目前時間與 false
+func (c *Collector) parseDate(str string) (time.Time, bool) {
 	if str == "" {
-		return time.Now().UTC()
+		return time.Now().UTC(), false
 	}
 
 	str = strings.TrimSpace(str)
@@ -136,11 +152,11 @@ func (c *Collector) parseDate(str string) time.Time {
 
 	for _, e := range arr {
 		if t, err := time.Parse(e, str); err == nil {
-			return t.UTC()
+			return t.UTC(), true
 		}
 	}
 
-	return time.Now().UTC()
+	return time.Now().UTC(), false
 }
 
 func (c *Collector) clean(content string) string {
@@ -0,0 +1,71 @@
+package util
+
+import (
+	"strings"
+)
+
+const (
+	DiffEqual  = ' '
+	DiffInsert = '+'
+	DiffDelete = '-'
+)
+
+type DiffLine struct {
+	Op   byte
+	Text string
+}
+
+// 以最長共同子序列逐行比較兩份文字，空白行不列入比較
+func Diff(before, after string) []DiffLine {
+	a := diffLines(before)
+	b := diffLines(after)
+
+	// lcs[i][j] 為 a[i:] 與 b[j:] 的最長共同子序列長度
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var result []DiffLine
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			result = append(result, DiffLine{Op: DiffEqual, Text: b[j]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			result = append(result, DiffLine{Op: DiffDelete, Text: a[i]})
+			i++
+		default:
+			result = append(result, DiffLine{Op: DiffInsert, Text: b[j]})
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		result = append(result, DiffLine{Op: DiffDelete, Text: a[i]})
+	}
+	for ; j < len(b); j++ {
+		result = append(result, DiffLine{Op: DiffInsert, Text: b[j]})
+	}
+	return result
+}
+
+func diffLines(str string) []string {
+	var lines []string
+	for _, line := range strings.Split(str, "\n") {
+		if line = strings.TrimRight(line, " \t\r"); strings.TrimSpace(line) != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
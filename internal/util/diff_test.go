@@ -0,0 +1,61 @@
+package util
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	tests := []struct {
+		name          string
+		before, after string
+		want          []DiffLine
+	}{
+		{
+			name: "empty",
+			want: nil,
+		},
+		{
+			name:   "identical",
+			before: "a\nb",
+			after:  "a\nb",
+			want:   []DiffLine{{DiffEqual, "a"}, {DiffEqual, "b"}},
+		},
+		{
+			name:   "insert",
+			before: "a\nc",
+			after:  "a\nb\nc",
+			want:   []DiffLine{{DiffEqual, "a"}, {DiffInsert, "b"}, {DiffEqual, "c"}},
+		},
+		{
+			name:   "delete",
+			before: "a\nb\nc",
+			after:  "a\nc",
+			want:   []DiffLine{{DiffEqual, "a"}, {DiffDelete, "b"}, {DiffEqual, "c"}},
+		},
+		{
+			name:   "replace",
+			before: "## A\n- one\n- two",
+			after:  "## A\n- one\n- two changed",
+			want:   []DiffLine{{DiffEqual, "## A"}, {DiffEqual, "- one"}, {DiffDelete, "- two"}, {DiffInsert, "- two changed"}},
+		},
+		{
+			name:  "from nothing",
+			after: "a\nb",
+			want:  []DiffLine{{DiffInsert, "a"}, {DiffInsert, "b"}},
+		},
+		{
+			name:   "blank lines and trailing spaces ignored",
+			before: "a  \n\n\nb\r\n",
+			after:  "a\nb",
+			want:   []DiffLine{{DiffEqual, "a"}, {DiffEqual, "b"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Diff(tt.before, tt.after); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Diff = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,50 @@
+package util
+
+import (
+	"regexp"
+	"strings"
+)
+
+// 將文字切成詞（中日韓文以字為單位），用於衡量擷取品質
+var tokenRegex = regexp.MustCompile(`\p{Han}|\p{Hiragana}|\p{Katakana}|\p{Hangul}|[\p{L}\p{N}]+`)
+
+// 比較擷取結果與預期正文，回傳 precision、recall 與 F1
+func ScoreExtraction(got, want string) (float64, float64, float64) {
+	gotTokens := countTokens(got)
+	wantTokens := countTokens(want)
+
+	matched := 0
+	for token, n := range gotTokens {
+		matched += min(n, wantTokens[token])
+	}
+
+	precision := ratio(matched, sumTokens(gotTokens))
+	recall := ratio(matched, sumTokens(wantTokens))
+	if precision+recall == 0 {
+		return precision, recall, 0
+	}
+	return precision, recall, 2 * precision * recall / (precision + recall)
+}
+
+func countTokens(str string) map[string]int {
+	result := make(map[string]int)
+	for _, token := range tokenRegex.FindAllString(strings.ToLower(str), -1) {
+		result[token]++
+	}
+	return result
+}
+
+func sumTokens(tokens map[string]int) int {
+	n := 0
+	for _, c := range tokens {
+		n += c
+	}
+	return n
+}
+
+func ratio(a, b int) float64 {
+	if b == 0 {
+		return 0
+	}
+	return float64(a) / float64(b)
+}
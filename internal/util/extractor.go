@@ -1,77 +1,258 @@
 package util
 
 import (
+	"context"
+	"fmt"
+	"io"
+	"log"
 	"net/http"
-	"regexp"
-	"rss-reader/internal/model"
 	"strings"
+	"sync"
 	"time"
 
+	"rss-reader/internal/config"
+	"rss-reader/internal/model"
+
 	"github.com/PuerkitoBio/goquery"
+	"golang.org/x/net/html"
 )
 
+// 伺服器回傳錯誤狀態碼
+type StatusError struct {
+	URL  string
+	Code int
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("unexpected status %d %s", e.Code, http.StatusText(e.Code))
+}
+
 type Extractor struct {
 	client *http.Client
+	mu     sync.RWMutex
+	rules  []Rule
 }
 
 func NewExtractor() *Extractor {
-	return &Extractor{
-		client: &http.Client{
-			Timeout: 30 * time.Second,
-		},
+	e := &Extractor{
+		client: &http.Client{},
+	}
+	if err := e.ReloadRules(); err != nil {
+		log.Printf("Failed to load extraction rules: %v", err)
+	}
+	return e
+}
+
+// 重新讀取網站擷取規則
+func (e *Extractor) ReloadRules() error {
+	rules, err := LoadRules(config.RulesPath())
+	if err != nil {
+		return err
 	}
+
+	e.mu.Lock()
+	e.rules = rules
+	e.mu.Unlock()
+	return nil
 }
 
-func (e *Extractor) Get(url string) (*model.NewsContent, error) {
-	res, err := e.client.Get(url)
+func (e *Extractor) Rules() []Rule {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return append([]Rule(nil), e.rules...)
+}
+
+func (e *Extractor) Get(ctx context.Context, url string) (*model.NewsContent, error) {
+	return e.GetWithRule(ctx, url, findRule(e.Rules(), url))
+}
+
+// 以指定規則擷取，rule 為 nil 時使用通用演算法；分頁文章會依序抓取後續頁面合併
+func (e *Extractor) GetWithRule(ctx context.Context, url string, rule *Rule) (*model.NewsContent, error) {
+	doc, status, err := e.fetch(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+
+	// 分頁列會在擷取正文時被移除，需先取得下一頁
+	next := nextPage(doc, url)
+	content, info, err := e.parseDocument(doc, rule)
 	if err != nil {
 		return nil, err
 	}
+	content.HTTPStatus = status
+
+	cfg := config.Get()
+	pages := []string{content.Content}
+	visited := map[string]bool{url: true}
+
+	for len(pages) < cfg.Extractor.MaxPages && next != "" && !visited[next] {
+		visited[next] = true
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(cfg.Extractor.Delay):
+		}
+
+		doc, _, err := e.fetch(ctx, next)
+		if err != nil {
+			log.Printf("Failed to get page %s: %v", next, err)
+			break
+		}
+
+		following := nextPage(doc, next)
+		page, _, err := e.parseDocument(doc, rule)
+		if err != nil {
+			break
+		}
+		pages = append(pages, page.Content)
+		next = following
+	}
+
+	if len(pages) > 1 {
+		content.Content = e.clean(stitch(pages))
+		content.WordCount = e.count(content.Content)
+		content.ReadingTime = readingTime(content.Content)
+		content.Confidence = confidence(content.Content, content.WordCount)
+		// 第一頁可能因內容不完整被判為付費牆，合併後需重新判斷
+		content.Language = resolveLanguage(info.language, content.Content)
+		content.Status = info.blocked.status(content.Content, content.WordCount, content.Confidence)
+	}
+	return content, nil
+}
+
+// 下載並解析頁面，回傳 HTTP 狀態碼
+func (e *Extractor) fetch(ctx context.Context, url string) (*goquery.Document, int, error) {
+	ctx, cancel := context.WithTimeout(ctx, config.Get().HTTP.Timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	res, err := e.client.Do(req)
+	if err != nil {
+		return nil, 0, err
+	}
 	defer res.Body.Close()
 
+	if res.StatusCode >= http.StatusBadRequest {
+		return nil, res.StatusCode, &StatusError{URL: url, Code: res.StatusCode}
+	}
+
 	doc, err := goquery.NewDocumentFromReader(res.Body)
+	return doc, res.StatusCode, err
+}
+
+// 解析 HTML 並擷取標題、作者與正文，pageURL 用於比對網站規則
+func (e *Extractor) Parse(r io.Reader, pageURL string) (*model.NewsContent, error) {
+	doc, err := goquery.NewDocumentFromReader(r)
 	if err != nil {
 		return nil, err
 	}
+	content, _, err := e.parseDocument(doc, findRule(e.Rules(), pageURL))
+	return content, err
+}
 
-	doc.Find("script, style, nav, aside, footer, .advertisement, .ads, .comment").Remove()
+// 合併分頁後重新判斷狀態與語言所需的頁面資訊
+type pageInfo struct {
+	blocked  wall
+	language string
+}
+
+func (e *Extractor) parseDocument(doc *goquery.Document, rule *Rule) (*model.NewsContent, pageInfo, error) {
+	// 結構化資料與付費牆元素需在移除 script 與覆蓋層前讀取
+	meta := parseMetadata(doc)
+	blocked := detectWall(doc, meta)
+
+	if rule != nil {
+		for _, selector := range rule.Strip {
+			doc.Find(selector).Remove()
+		}
+	}
 
 	// 檢查 h1 是否存在
-	title := doc.Find("h1").First().Text()
+	title := selectText(doc, rule, func(r *Rule) string { return r.Title })
 	if title == "" {
-		// 如果 h1 不存在，則使用 title 標籤
-		title = doc.Find("title").Text()
+		title = doc.Find("h1").First().Text()
+	}
+	if strings.TrimSpace(title) == "" {
+		// 如果 h1 不存在，則使用結構化資料或 title 標籤
+		title = firstString(meta.Title, doc.Find("title").Text())
 	}
 
-	// 檢查作者信息
-	author := doc.Find("[rel='author'], .author, [itemprop='author']").First().Text()
+	// 檢查作者信息，網站規則與結構化資料優先
+	author := firstString(
+		selectText(doc, rule, func(r *Rule) string { return r.Author }),
+		meta.Author,
+		doc.Find("[rel='author'], .author, [itemprop='author']").First().Text(),
+	)
 
-	// 分析主內容
-	content := e.getContent(doc)
+	// 分析主內容，以 Markdown 保留段落結構
+	var nodes []*html.Node
+	strategy := model.StrategyReadability
+	if rule != nil && rule.Content != "" {
+		doc.Find("script, style, noscript").Remove()
+		if s := doc.Find(rule.Content); nodesLength(s.Nodes) > 0 {
+			nodes = s.Nodes
+			strategy = model.StrategyRule
+		}
+	}
+	if nodes == nil {
+		nodes = e.getContent(doc)
+	}
+	content := toMarkdown(nodes)
+	wordCount := e.count(content)
+	score := confidence(content, wordCount)
 
 	return &model.NewsContent{
-		Title:     strings.TrimSpace(title),
-		Author:    strings.TrimSpace(author),
-		Content:   e.clean(content),
-		WordCount: e.count(content),
-	}, nil
+		Title:       strings.TrimSpace(title),
+		Author:      strings.TrimSpace(author),
+		Content:     e.clean(content),
+		WordCount:   wordCount,
+		ReadingTime: readingTime(content),
+		PublishedAt: meta.PublishedAt,
+		Image:       meta.Image,
+		Section:     meta.Section,
+		Keywords:    meta.Keywords,
+		Language:    resolveLanguage(meta.Language, content),
+		Confidence:  score,
+		Status:      blocked.status(content, wordCount, score),
+		Strategy:    strategy,
+		AMP:         meta.AMP,
+	}, pageInfo{blocked: blocked, language: meta.Language}, nil
 }
 
-func (e *Extractor) getContent(doc *goquery.Document) string {
+func selectText(doc *goquery.Document, rule *Rule, field func(*Rule) string) string {
+	if rule == nil || field(rule) == "" {
+		return ""
+	}
+	return strings.TrimSpace(doc.Find(field(rule)).First().Text())
+}
+
+func (e *Extractor) getContent(doc *goquery.Document) []*html.Node {
 	// 超過 128 個字符的內容就假設為文章內容
 	contentMinLength := 128
 
+	if nodes := newReadability(doc).parse(); len(nodes) > 0 {
+		if nodesLength(nodes) > contentMinLength {
+			return nodes
+		}
+	}
+
+	// 評分失敗時退回以標籤判斷
 	// 檢查 article 標籤
-	if content := doc.Find("article").Text(); content != "" && len(content) > contentMinLength {
-		return content
+	if s := doc.Find("article"); len(s.Text()) > contentMinLength {
+		return s.Nodes
 	}
 	// 檢查 main 標籤
-	if content := doc.Find("main").Text(); content != "" && len(content) > contentMinLength {
-		return content
+	if s := doc.Find("main"); len(s.Text()) > contentMinLength {
+		return s.Nodes
 	}
 
 	// 檢查全部 div 標籤
-	content := ""
+	var content *goquery.Selection
 	maxLength := 0
 	doc.Find("div").Each(func(i int, s *goquery.Selection) {
 		text := s.Text()
@@ -84,30 +265,46 @@ func (e *Extractor) getContent(doc *goquery.Document) string {
 			percent := float64(len(link)) / float64(len(text))
 			if percent < 0.3 {
 				maxLength = len(text)
-				content = text
+				content = s
 			}
 		}
 	})
 
-	return content
+	if content == nil {
+		return nil
+	}
+	return content.Nodes
 }
 
-func (e *Extractor) clean(str string) string {
-	regex := regexp.MustCompile(`\s+`)
-	str = regex.ReplaceAllString(str, " ")
-	return strings.TrimSpace(str)
+func nodesLength(nodes []*html.Node) int {
+	length := 0
+	for _, n := range nodes {
+		length += len(textContent(n))
+	}
+	return length
 }
 
-func (e *Extractor) count(str string) int {
-	if str == "" {
-		return 0
+func isBlock(tag string) bool {
+	switch tag {
+	case "address", "article", "blockquote", "dd", "div", "dl", "dt", "figcaption", "figure",
+		"h1", "h2", "h3", "h4", "h5", "h6", "header", "hr", "li", "main", "ol", "p", "pre",
+		"section", "table", "tr", "td", "th", "ul":
+		return true
 	}
+	return false
+}
 
-	cnRegex := regexp.MustCompile(`[\p{Han}]`)
-	cnCount := len(cnRegex.FindAllString(str, -1))
-
-	enRegex := regexp.MustCompile(`[a-zA-Z]+`)
-	enCount := len(enRegex.FindAllString(str, -1))
+// 移除行尾空白與多餘空行，保留段落換行與程式碼縮排
+func (e *Extractor) clean(str string) string {
+	lines := strings.Split(str, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, " \t")
+	}
+	str = blankLineRegex.ReplaceAllString(strings.Join(lines, "\n"), "\n\n")
+	return strings.TrimSpace(str)
+}
 
-	return cnCount + enCount
+func (e *Extractor) count(str string) int {
+	words, cjk := countWords(str)
+	return words + cjk
 }
@@ -0,0 +1,164 @@
+package util
+
+import (
+	"context"
+	"errors"
+	"math"
+	"net/url"
+	"strings"
+
+	"rss-reader/internal/model"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// 信心分數達到此值即採用，否則繼續嘗試下一個來源
+const minConfidence = 0.5
+
+// RSS 摘要只是導言，分數不超過此值
+const descriptionConfidence = 0.3
+
+// 依序嘗試 RSS content:encoded、網頁正文（網站規則或 readability）、AMP 版本與 RSS 摘要，
+// 採用第一個達到信心門檻的來源，都未達到時採用分數最高者。
+// 回傳的內容不為 nil，error 為網頁擷取失敗的原因，供呼叫端判斷是否重試
+func (e *Extractor) Extract(ctx context.Context, news model.News) (*model.NewsContent, error) {
+	page, pageErr := e.Get(ctx, news.URL)
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	// 標題、作者等資訊以網頁為準
+	result := &model.NewsContent{}
+	if page != nil {
+		result = page
+	}
+	var status *StatusError
+	if errors.As(pageErr, &status) {
+		result.HTTPStatus = status.Code
+	}
+
+	var candidates []*model.NewsContent
+	if news.Encoded != "" {
+		candidates = append(candidates, e.fromHTML(news.Encoded, model.StrategyFeed))
+	}
+	// 被付費牆或同意頁擋住的正文不列入候選
+	blocked := ""
+	if page != nil && page.Status != "" {
+		blocked = page.Status
+	} else if page != nil {
+		candidates = append(candidates, page)
+	}
+	if best(candidates) == nil && page != nil && page.AMP != "" {
+		if amp, err := e.Get(ctx, resolveURL(news.URL, page.AMP)); err == nil && amp.Status == "" {
+			amp.Strategy = model.StrategyAMP
+			candidates = append(candidates, amp)
+		}
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+	}
+	if news.Content != "" {
+		description := &model.NewsContent{
+			Content:     news.Content,
+			WordCount:   e.count(news.Content),
+			ReadingTime: readingTime(news.Content),
+			Strategy:    model.StrategyDescription,
+		}
+		description.Confidence = math.Min(confidence(description.Content, description.WordCount), descriptionConfidence)
+		candidates = append(candidates, description)
+	}
+
+	chosen := best(candidates)
+	if chosen == nil {
+		chosen = highest(candidates)
+	}
+
+	// chosen 可能就是 result，先複製再覆寫
+	var text, strategy string
+	var wordCount, minutes int
+	var score float64
+	if chosen != nil {
+		text, wordCount, minutes, score, strategy = chosen.Content, chosen.WordCount, chosen.ReadingTime, chosen.Confidence, chosen.Strategy
+	}
+	result.Content, result.WordCount, result.ReadingTime, result.Confidence, result.Strategy = text, wordCount, minutes, score, strategy
+	if result.Language == "" || (chosen != nil && chosen != page) {
+		result.Language = resolveLanguage(result.Language, text)
+	}
+
+	switch {
+	case chosen != nil && chosen.Strategy != model.StrategyDescription:
+		result.Status = model.StatusSuccess
+	case blocked != "":
+		result.Status = blocked
+	case pageErr != nil:
+		result.Status = model.StatusError
+	default:
+		result.Status = model.StatusEmpty
+	}
+	return result, pageErr
+}
+
+// 將 RSS 內的 HTML 轉為 Markdown 正文
+func (e *Extractor) fromHTML(str, strategy string) *model.NewsContent {
+	content := &model.NewsContent{Strategy: strategy}
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(str))
+	if err != nil {
+		return content
+	}
+	doc.Find("script, style, noscript").Remove()
+
+	content.Content = e.clean(toMarkdown(doc.Find("body").Nodes))
+	content.WordCount = e.count(content.Content)
+	content.ReadingTime = readingTime(content.Content)
+	content.Confidence = confidence(content.Content, content.WordCount)
+	return content
+}
+
+// 第一個達到信心門檻的來源
+func best(candidates []*model.NewsContent) *model.NewsContent {
+	for _, c := range candidates {
+		if c.Confidence >= minConfidence {
+			return c
+		}
+	}
+	return nil
+}
+
+func highest(candidates []*model.NewsContent) *model.NewsContent {
+	var result *model.NewsContent
+	for _, c := range candidates {
+		if c.Content != "" && (result == nil || c.Confidence > result.Confidence) {
+			result = c
+		}
+	}
+	return result
+}
+
+// 依字數與段落數估計正文是否完整，300 字與 4 個段落以上視為完整
+func confidence(content string, wordCount int) float64 {
+	if strings.TrimSpace(content) == "" {
+		return 0
+	}
+
+	paragraphs := 0
+	for _, block := range strings.Split(content, "\n\n") {
+		if len(strings.TrimSpace(block)) > 80 {
+			paragraphs++
+		}
+	}
+
+	score := 0.6*math.Min(1, float64(wordCount)/300) + 0.4*math.Min(1, float64(paragraphs)/4)
+	return math.Round(score*100) / 100
+}
+
+func resolveURL(base, ref string) string {
+	u, err := url.Parse(base)
+	if err != nil {
+		return ref
+	}
+	r, err := u.Parse(strings.TrimSpace(ref))
+	if err != nil {
+		return ref
+	}
+	return r.String()
+}
@@ -0,0 +1,136 @@
+package util
+
+import (
+	"strings"
+	"unicode"
+)
+
+// 繁簡中文各自常見、另一方不使用的字
+const (
+	traditionalChars = "這個們來說會為與點對時國後學發經實體關當開長還過現從無動種進給應這讓頭誰"
+	simplifiedChars  = "这个们来说会为与点对时国后学发经实体关当开长还过现从无动种进给应这让头谁"
+)
+
+// 拉丁字母語言的常見虛詞
+var stopwords = map[string][]string{
+	"en": {"the", "and", "of", "to", "in", "is", "that", "for", "with", "was", "on", "are"},
+	"fr": {"le", "la", "les", "et", "des", "est", "une", "dans", "que", "pour", "pas", "sur"},
+	"de": {"der", "die", "und", "das", "ist", "nicht", "mit", "den", "ein", "eine", "auf", "sich"},
+	"es": {"el", "la", "los", "las", "que", "del", "por", "una", "para", "con", "es", "se"},
+}
+
+// 以文字系統與虛詞判斷語言，無法判斷時回傳空字串
+func detectLanguage(content string) string {
+	var han, kana, hangul, latin int
+	var traditional, simplified int
+	for _, r := range content {
+		switch {
+		case unicode.In(r, unicode.Hiragana, unicode.Katakana):
+			kana++
+		case unicode.Is(unicode.Han, r):
+			han++
+			if strings.ContainsRune(traditionalChars, r) {
+				traditional++
+			} else if strings.ContainsRune(simplifiedChars, r) {
+				simplified++
+			}
+		case unicode.Is(unicode.Hangul, r):
+			hangul++
+		case unicode.Is(unicode.Latin, r):
+			latin++
+		}
+	}
+
+	total := han + kana + hangul + latin
+	if total < 20 {
+		return ""
+	}
+
+	switch {
+	// 日文漢字與假名混用，假名比例通常超過兩成
+	case kana > 0 && kana*5 >= han+kana && (han+kana)*2 > total:
+		return "ja"
+	case hangul*2 > total:
+		return "ko"
+	case han*2 > total:
+		if simplified > traditional {
+			return "zh-Hans"
+		}
+		if traditional > simplified {
+			return "zh-Hant"
+		}
+		return "zh"
+	case latin*2 > total:
+		return latinLanguage(content)
+	}
+	return ""
+}
+
+func latinLanguage(content string) string {
+	counts := make(map[string]int)
+	for _, word := range strings.FieldsFunc(strings.ToLower(content), func(r rune) bool {
+		return !unicode.IsLetter(r)
+	}) {
+		for lang, words := range stopwords {
+			for _, w := range words {
+				if word == w {
+					counts[lang]++
+				}
+			}
+		}
+	}
+
+	best, bestCount := "", 0
+	for _, lang := range []string{"en", "fr", "de", "es"} {
+		if counts[lang] > bestCount {
+			best, bestCount = lang, counts[lang]
+		}
+	}
+	if bestCount < 3 {
+		return ""
+	}
+	return best
+}
+
+// 頁面宣告的語言與內容判斷的文字系統不符時（如英文模板的中文文章），以內容為準
+func resolveLanguage(declared, content string) string {
+	declared = normalizeLanguage(declared)
+	detected := detectLanguage(content)
+
+	switch {
+	case declared == "":
+		return detected
+	case detected == "":
+		return declared
+	case PrimaryLanguage(declared) != PrimaryLanguage(detected) && PrimaryLanguage(detected) != "en":
+		return detected
+	}
+	return declared
+}
+
+// 統一為 BCP 47 大小寫，如 zh_tw → zh-TW、zh-hant → zh-Hant
+func normalizeLanguage(code string) string {
+	parts := strings.Split(strings.ReplaceAll(strings.TrimSpace(code), "_", "-"), "-")
+	if parts[0] == "" {
+		return ""
+	}
+	for i, part := range parts {
+		switch {
+		case i == 0:
+			parts[i] = strings.ToLower(part)
+		case len(part) == 4:
+			parts[i] = strings.ToUpper(part[:1]) + strings.ToLower(part[1:])
+		case len(part) == 2:
+			parts[i] = strings.ToUpper(part)
+		default:
+			parts[i] = strings.ToLower(part)
+		}
+	}
+	return strings.Join(parts, "-")
+}
+
+// 主要語言代碼，如 zh-TW → zh
+func PrimaryLanguage(code string) string {
+	primary, _, _ := strings.Cut(normalizeLanguage(code), "-")
+	return primary
+}
@@ -0,0 +1,74 @@
+package util
+
+import "testing"
+
+func TestDetectLanguage(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"too short", "Hello", ""},
+		{"traditional chinese", "這個颱風會為北部地區帶來豪雨，民眾應注意坍方與落石，並避免前往山區及海邊。", "zh-Hant"},
+		{"simplified chinese", "这个台风会为北部地区带来暴雨，民众应注意塌方与落石，并避免前往山区及海边。", "zh-Hans"},
+		{"japanese", "利用者の減少が続く地方鉄道の運行会社が、来年春のダイヤ改正で運行本数を見直す方針です。", "ja"},
+		{"korean", "서울은 오늘 맑은 날씨가 이어지겠고 오후에는 기온이 크게 오르겠습니다.", "ko"},
+		{"english", "The committee said that the plan was approved and is expected to take effect in the spring.", "en"},
+		{"french", "Le gouvernement a présenté les mesures dans une conférence et le texte est pour la semaine prochaine.", "fr"},
+		{"german", "Die Regierung hat die Pläne vorgestellt und das Gesetz ist nicht mit den Ländern abgestimmt.", "de"},
+		{"spanish", "El gobierno presentó las medidas para los próximos meses y la ley se votará con el apoyo del congreso.", "es"},
+		{"latin without stopwords", "Lorem ipsum dolor sit amet consectetur adipiscing elit", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := detectLanguage(tt.in); got != tt.want {
+				t.Errorf("detectLanguage = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNormalizeLanguage(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"", ""},
+		{"  ", ""},
+		{"EN", "en"},
+		{"zh_tw", "zh-TW"},
+		{"zh-hant", "zh-Hant"},
+		{"zh-hant-tw", "zh-Hant-TW"},
+		{"en-us", "en-US"},
+		{"es-419", "es-419"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			if got := normalizeLanguage(tt.in); got != tt.want {
+				t.Errorf("normalizeLanguage(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolveLanguage(t *testing.T) {
+	chinese := "這個颱風會為北部地區帶來豪雨，民眾應注意坍方與落石，並避免前往山區及海邊。"
+	english := "The committee said that the plan was approved and is expected to take effect in the spring."
+	tests := []struct {
+		name              string
+		declared, content string
+		want              string
+	}{
+		{"declared only", "zh_TW", "", "zh-TW"},
+		{"detected only", "", chinese, "zh-Hant"},
+		{"declared matches script", "zh-TW", chinese, "zh-TW"},
+		{"english template with chinese text", "en", chinese, "zh-Hant"},
+		{"english text keeps declared", "de", english, "de"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := resolveLanguage(tt.declared, tt.content); got != tt.want {
+				t.Errorf("resolveLanguage(%q) = %q, want %q", tt.declared, got, tt.want)
+			}
+		})
+	}
+}
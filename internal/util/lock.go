@@ -0,0 +1,78 @@
+package util
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// 確保同一時間只有一個程序（TUI 或 daemon）負責寫入
+type Lock struct {
+	path string
+}
+
+type LockedError struct {
+	PID int
+}
+
+func (e *LockedError) Error() string {
+	return fmt.Sprintf("another rss-reader process is running (PID %d)", e.PID)
+}
+
+func LockPath(dbPath string) string {
+	return filepath.Join(filepath.Dir(dbPath), "rss-reader.pid")
+}
+
+// 先將 PID 寫入暫存檔再以 hard link 放到鎖定檔位置，其他程序不會讀到尚未寫入 PID 的鎖定檔
+func AcquireLock(path string) (*Lock, error) {
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".rss-reader.pid.*")
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(tmp.Name())
+	_, err = tmp.WriteString(strconv.Itoa(os.Getpid()))
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	for i := 0; i < 2; i++ {
+		err := os.Link(tmp.Name(), path)
+		if err == nil {
+			return &Lock{path: path}, nil
+		}
+		if !errors.Is(err, os.ErrExist) {
+			return nil, err
+		}
+
+		data, err := os.ReadFile(path)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		pid, err := strconv.Atoi(strings.TrimSpace(string(data)))
+		if err == nil && pid != os.Getpid() && processAlive(pid) {
+			return nil, &LockedError{PID: pid}
+		}
+
+		// 前一個程序未正常結束，移除殘留的鎖定檔後重試
+		if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return nil, err
+		}
+	}
+	return nil, fmt.Errorf("failed to acquire lock %s", path)
+}
+
+func (l *Lock) Release() error {
+	if l == nil {
+		return nil
+	}
+	return os.Remove(l.path)
+}
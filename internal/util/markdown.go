@@ -0,0 +1,282 @@
+package util
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"golang.org/x/net/html"
+)
+
+// 將正文節點轉為 Markdown，保留段落、標題、列表、引言與程式碼區塊
+
+var (
+	spaceRegex     = regexp.MustCompile(`[ \t\r\f\v\x{00a0}\x{3000}]+`)
+	blankLineRegex = regexp.MustCompile(`\n{3,}`)
+)
+
+type markdown struct {
+	sb strings.Builder
+}
+
+func toMarkdown(nodes []*html.Node) string {
+	m := &markdown{}
+	for _, n := range nodes {
+		m.block(n, "")
+	}
+	return strings.TrimSpace(blankLineRegex.ReplaceAllString(m.sb.String(), "\n\n"))
+}
+
+func (m *markdown) block(n *html.Node, prefix string) {
+	if n.Type == html.TextNode {
+		if text := strings.TrimSpace(collapse(n.Data)); text != "" {
+			m.paragraph(text, prefix)
+		}
+		return
+	}
+	if n.Type != html.ElementNode && n.Type != html.DocumentNode {
+		return
+	}
+
+	switch n.Data {
+	case "h1", "h2", "h3", "h4", "h5", "h6":
+		if text := m.inline(n); text != "" {
+			level := int(n.Data[1] - '0')
+			m.paragraph(strings.Repeat("#", level)+" "+text, prefix)
+		}
+
+	case "p", "dt", "dd", "figcaption", "caption":
+		if text := m.inline(n); text != "" {
+			m.paragraph(text, prefix)
+		}
+
+	case "pre":
+		code := strings.Trim(textContent(n), "\n")
+		if strings.TrimSpace(code) == "" {
+			return
+		}
+		m.sb.WriteString(prefix + "```\n")
+		for _, line := range strings.Split(code, "\n") {
+			m.sb.WriteString(prefix + line + "\n")
+		}
+		m.sb.WriteString(prefix + "```\n\n")
+
+	case "blockquote":
+		m.children(n, prefix+"> ")
+
+	case "ul", "ol":
+		m.list(n, prefix)
+		m.sb.WriteString("\n")
+
+	case "table":
+		for _, row := range findAll(n, "tr") {
+			var cells []string
+			for c := row.FirstChild; c != nil; c = c.NextSibling {
+				if c.Type == html.ElementNode && (c.Data == "td" || c.Data == "th") {
+					cells = append(cells, m.inline(c))
+				}
+			}
+			if len(cells) > 0 {
+				m.sb.WriteString(prefix + "| " + strings.Join(cells, " | ") + " |\n")
+			}
+		}
+		m.sb.WriteString("\n")
+
+	case "hr":
+		m.sb.WriteString(prefix + "---\n\n")
+
+	case "img", "picture", "video", "audio", "svg", "script", "style", "noscript":
+		return
+
+	default:
+		// div 等容器：若只包含行內內容則視為段落，否則逐一處理子節點
+		if !hasBlockChild(n) {
+			if text := m.inline(n); text != "" {
+				m.paragraph(text, prefix)
+			}
+			return
+		}
+		m.children(n, prefix)
+	}
+}
+
+func (m *markdown) children(n *html.Node, prefix string) {
+	// 連續的行內節點合併為同一段落
+	var inline []*html.Node
+	flush := func() {
+		var sb strings.Builder
+		for _, c := range inline {
+			sb.WriteString(m.inlineNode(c))
+		}
+		if text := strings.TrimSpace(collapse(sb.String())); text != "" {
+			m.paragraph(text, prefix)
+		}
+		inline = inline[:0]
+	}
+
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if c.Type == html.ElementNode && isBlock(c.Data) {
+			flush()
+			m.block(c, prefix)
+			continue
+		}
+		inline = append(inline, c)
+	}
+	flush()
+}
+
+// 巢狀列表依標記寬度縮排，只有最外層列表後加空行
+func (m *markdown) list(n *html.Node, prefix string) {
+	index := 1
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if c.Type != html.ElementNode || c.Data != "li" {
+			continue
+		}
+		marker := "- "
+		if n.Data == "ol" {
+			marker = fmt.Sprintf("%d. ", index)
+			index++
+		}
+		indent := prefix + strings.Repeat(" ", len(marker))
+
+		var sb strings.Builder
+		var nested []*html.Node
+		for item := c.FirstChild; item != nil; item = item.NextSibling {
+			if item.Type == html.ElementNode && (item.Data == "ul" || item.Data == "ol") {
+				nested = append(nested, item)
+				continue
+			}
+			sb.WriteString(m.inlineNode(item))
+		}
+
+		if text := normalizeInline(sb.String()); text != "" {
+			for i, line := range strings.Split(text, "\n") {
+				if i == 0 {
+					m.sb.WriteString(prefix + marker + line + "\n")
+				} else if line != "" {
+					m.sb.WriteString(indent + line + "\n")
+				}
+			}
+		} else if len(nested) > 0 {
+			m.sb.WriteString(prefix + marker + "\n")
+		}
+		for _, list := range nested {
+			m.list(list, indent)
+		}
+	}
+}
+
+func (m *markdown) paragraph(text, prefix string) {
+	for _, line := range strings.Split(text, "\n") {
+		m.sb.WriteString(prefix + strings.TrimSpace(line) + "\n")
+	}
+	m.sb.WriteString("\n")
+}
+
+func (m *markdown) inline(n *html.Node) string {
+	return normalizeInline(m.rawInline(n))
+}
+
+// 未去除前後空白的行內內容
+func (m *markdown) rawInline(n *html.Node) string {
+	var sb strings.Builder
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		sb.WriteString(m.inlineNode(c))
+	}
+	return sb.String()
+}
+
+func normalizeInline(str string) string {
+	lines := strings.Split(str, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimSpace(collapse(line))
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
+
+func (m *markdown) inlineNode(n *html.Node) string {
+	switch n.Type {
+	case html.TextNode:
+		return collapse(strings.ReplaceAll(n.Data, "\n", " "))
+	case html.ElementNode:
+	default:
+		return ""
+	}
+
+	switch n.Data {
+	case "br":
+		return "\n"
+	case "img", "script", "style", "noscript", "svg":
+		return ""
+	}
+
+	raw := m.rawInline(n)
+	text := normalizeInline(raw)
+	if text == "" {
+		return raw
+	}
+	switch n.Data {
+	case "strong", "b":
+		return emphasis(raw, text, "**")
+	case "em", "i":
+		return emphasis(raw, text, "*")
+	case "code", "kbd":
+		return emphasis(raw, text, "`")
+	}
+	if isBlock(n.Data) {
+		return "\n" + text + "\n"
+	}
+	return raw
+}
+
+// 標記緊貼文字，元素內前後的空白移到標記外，避免在中文句子或詞中插入空格
+func emphasis(raw, text, marker string) string {
+	var lead, trail string
+	if trimmed := strings.TrimLeftFunc(raw, unicode.IsSpace); len(trimmed) < len(raw) {
+		lead = " "
+	}
+	if trimmed := strings.TrimRightFunc(raw, unicode.IsSpace); len(trimmed) < len(raw) {
+		trail = " "
+	}
+	return lead + marker + text + marker + trail
+}
+
+func hasBlockChild(n *html.Node) bool {
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if c.Type == html.ElementNode && isBlock(c.Data) {
+			return true
+		}
+	}
+	return false
+}
+
+func findAll(n *html.Node, tag string) []*html.Node {
+	var arr []*html.Node
+	var walk func(*html.Node)
+	walk = func(n *html.Node) {
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			if c.Type == html.ElementNode && c.Data == tag {
+				arr = append(arr, c)
+			}
+			walk(c)
+		}
+	}
+	walk(n)
+	return arr
+}
+
+func textContent(n *html.Node) string {
+	if n.Type == html.TextNode {
+		return n.Data
+	}
+	var sb strings.Builder
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		sb.WriteString(textContent(c))
+	}
+	return sb.String()
+}
+
+func collapse(str string) string {
+	return spaceRegex.ReplaceAllString(str, " ")
+}
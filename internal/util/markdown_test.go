@@ -0,0 +1,70 @@
+package util
+
+import (
+	"strings"
+	"testing"
+
+	"golang.org/x/net/html"
+)
+
+func TestToMarkdown(t *testing.T) {
+	tests := []struct {
+		name string
+		html string
+		want string
+	}{
+		{
+			name: "cjk emphasis",
+			html: `<p>今天<strong>颱風</strong>登陸</p>`,
+			want: "今天**颱風**登陸",
+		},
+		{
+			name: "latin emphasis",
+			html: `<p>This is <em>very</em> important and <b>bold </b>text</p>`,
+			want: "This is *very* important and **bold** text",
+		},
+		{
+			name: "intraword emphasis",
+			html: `<p>un<b>believ</b>able</p>`,
+			want: "un**believ**able",
+		},
+		{
+			name: "inline code",
+			html: `<p>Run <code>go test</code> first</p>`,
+			want: "Run `go test` first",
+		},
+		{
+			name: "link keeps spaces",
+			html: `<p>See<a href="/x"> the docs </a>for details</p>`,
+			want: "See the docs for details",
+		},
+		{
+			name: "nested list",
+			html: `<ul><li>one<ul><li>one a</li><li>one b<ol><li>deep</li></ol></li></ul></li><li>two</li></ul>`,
+			want: "- one\n  - one a\n  - one b\n    1. deep\n- two",
+		},
+		{
+			name: "ordered list indent",
+			html: `<ol><li>first<ul><li>child</li></ul></li><li>second</li></ol>`,
+			want: "1. first\n   - child\n2. second",
+		},
+		{
+			name: "heading and paragraph",
+			html: `<div><h2>Title</h2><p>Body text.</p></div>`,
+			want: "## Title\n\nBody text.",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := html.Parse(strings.NewReader("<html><body>" + tt.html + "</body></html>"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			body := findAll(doc, "body")[0]
+			if got := toMarkdown([]*html.Node{body}); got != tt.want {
+				t.Errorf("got\n%q\nwant\n%q", got, tt.want)
+			}
+		})
+	}
+}
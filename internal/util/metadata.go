@@ -0,0 +1,319 @@
+package util
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// 從 JSON-LD、OpenGraph、Twitter card 與一般 meta 標籤取得文章資訊，
+// 優先順序為 JSON-LD > OpenGraph/Twitter > meta > 頁面元素
+type metadata struct {
+	Title       string
+	Author      string
+	PublishedAt time.Time
+	Image       string
+	Section     string
+	Keywords    []string
+	Language    string
+	AMP         string
+	// schema.org isAccessibleForFree 為 false
+	NotFree bool
+}
+
+var articleTypes = map[string]bool{
+	"Article":               true,
+	"NewsArticle":           true,
+	"BlogPosting":           true,
+	"ReportageNewsArticle":  true,
+	"AnalysisNewsArticle":   true,
+	"OpinionNewsArticle":    true,
+	"BackgroundNewsArticle": true,
+	"LiveBlogPosting":       true,
+	"TechArticle":           true,
+	"Report":                true,
+}
+
+var dateLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05Z0700",
+	"2006-01-02T15:04:05",
+	"2006-01-02T15:04Z07:00",
+	"2006-01-02 15:04:05 -0700",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006/01/02 15:04:05",
+	"2006/01/02 15:04",
+	"2006-01-02",
+	time.RFC1123Z,
+	time.RFC1123,
+}
+
+// 需在移除 script 之前呼叫
+func parseMetadata(doc *goquery.Document) *metadata {
+	m := &metadata{}
+	m.jsonLD(doc)
+	m.meta(doc)
+
+	if m.Language == "" {
+		m.Language, _ = doc.Find("html").Attr("lang")
+	}
+	if m.PublishedAt.IsZero() {
+		if value, ok := doc.Find("article time[datetime], time[itemprop='datePublished'], [itemprop='datePublished']").First().Attr("datetime"); ok {
+			m.PublishedAt = parseTime(value)
+		}
+	}
+
+	m.AMP, _ = doc.Find("link[rel='amphtml']").Attr("href")
+
+	m.Language = strings.TrimSpace(m.Language)
+	m.Keywords = uniqueStrings(m.Keywords)
+	return m
+}
+
+func (m *metadata) jsonLD(doc *goquery.Document) {
+	doc.Find("script[type='application/ld+json']").EachWithBreak(func(i int, s *goquery.Selection) bool {
+		var raw any
+		if err := json.Unmarshal([]byte(s.Text()), &raw); err != nil {
+			return true
+		}
+
+		for _, obj := range flattenLD(raw) {
+			if !isArticleType(obj["@type"]) {
+				continue
+			}
+
+			m.Title = firstString(m.Title, ldString(obj["headline"]))
+			m.Author = firstString(m.Author, ldName(obj["author"]))
+			m.Image = firstString(m.Image, ldURL(obj["image"]))
+			m.Section = firstString(m.Section, ldString(obj["articleSection"]))
+			m.Language = firstString(m.Language, ldString(obj["inLanguage"]))
+			if m.PublishedAt.IsZero() {
+				m.PublishedAt = parseTime(ldString(obj["datePublished"]))
+			}
+			if len(m.Keywords) == 0 {
+				m.Keywords = ldList(obj["keywords"])
+			}
+			m.NotFree = m.NotFree || ldFalse(obj["isAccessibleForFree"]) || ldPartNotFree(obj["hasPart"])
+			return false
+		}
+		return true
+	})
+}
+
+func (m *metadata) meta(doc *goquery.Document) {
+	values := make(map[string][]string)
+	doc.Find("meta").Each(func(i int, s *goquery.Selection) {
+		key, ok := s.Attr("property")
+		if !ok {
+			key, ok = s.Attr("name")
+		}
+		if !ok {
+			key, ok = s.Attr("itemprop")
+		}
+		content, hasContent := s.Attr("content")
+		if !ok || !hasContent {
+			return
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+		content = strings.TrimSpace(content)
+		if content != "" {
+			values[key] = append(values[key], content)
+		}
+	})
+
+	get := func(keys ...string) string {
+		for _, key := range keys {
+			if v := values[key]; len(v) > 0 {
+				return v[0]
+			}
+		}
+		return ""
+	}
+
+	m.Title = firstString(m.Title, get("og:title", "twitter:title"))
+	m.Image = firstString(m.Image, get("og:image", "og:image:url", "twitter:image", "twitter:image:src"))
+	m.Section = firstString(m.Section, get("article:section"))
+
+	// article:author 常是作者頁面網址，不適合作為作者名稱
+	author := get("author", "article:author", "twitter:creator", "dc.creator")
+	if !strings.HasPrefix(author, "http") {
+		m.Author = firstString(m.Author, author)
+	}
+
+	if m.PublishedAt.IsZero() {
+		m.PublishedAt = parseTime(get("article:published_time", "datepublished", "pubdate", "publishdate", "publish-date", "dc.date.issued", "date"))
+	}
+
+	if len(m.Keywords) == 0 {
+		m.Keywords = values["article:tag"]
+	}
+	if len(m.Keywords) == 0 {
+		m.Keywords = splitKeywords(get("keywords", "news_keywords"))
+	}
+
+	// og:locale 使用底線，如 zh_TW
+	m.Language = firstString(m.Language, strings.ReplaceAll(get("og:locale", "content-language", "dc.language"), "_", "-"))
+}
+
+func flattenLD(raw any) []map[string]any {
+	var arr []map[string]any
+	switch v := raw.(type) {
+	case []any:
+		for _, e := range v {
+			arr = append(arr, flattenLD(e)...)
+		}
+	case map[string]any:
+		arr = append(arr, v)
+		if graph, ok := v["@graph"]; ok {
+			arr = append(arr, flattenLD(graph)...)
+		}
+	}
+	return arr
+}
+
+func isArticleType(raw any) bool {
+	switch v := raw.(type) {
+	case string:
+		return articleTypes[v]
+	case []any:
+		for _, e := range v {
+			if s, ok := e.(string); ok && articleTypes[s] {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func ldString(raw any) string {
+	switch v := raw.(type) {
+	case string:
+		return strings.TrimSpace(v)
+	case []any:
+		if len(v) > 0 {
+			return ldString(v[0])
+		}
+	}
+	return ""
+}
+
+func ldName(raw any) string {
+	switch v := raw.(type) {
+	case string:
+		return strings.TrimSpace(v)
+	case map[string]any:
+		return ldString(v["name"])
+	case []any:
+		var names []string
+		for _, e := range v {
+			if name := ldName(e); name != "" {
+				names = append(names, name)
+			}
+		}
+		return strings.Join(names, ", ")
+	}
+	return ""
+}
+
+func ldURL(raw any) string {
+	switch v := raw.(type) {
+	case string:
+		return strings.TrimSpace(v)
+	case map[string]any:
+		return ldString(v["url"])
+	case []any:
+		if len(v) > 0 {
+			return ldURL(v[0])
+		}
+	}
+	return ""
+}
+
+// isAccessibleForFree 可能是布林值或 "False" 字串
+func ldFalse(raw any) bool {
+	switch v := raw.(type) {
+	case bool:
+		return !v
+	case string:
+		return strings.EqualFold(strings.TrimSpace(v), "false")
+	}
+	return false
+}
+
+func ldPartNotFree(raw any) bool {
+	for _, part := range flattenLD(raw) {
+		if ldFalse(part["isAccessibleForFree"]) {
+			return true
+		}
+	}
+	return false
+}
+
+func ldList(raw any) []string {
+	switch v := raw.(type) {
+	case string:
+		return splitKeywords(v)
+	case []any:
+		var arr []string
+		for _, e := range v {
+			if s := ldString(e); s != "" {
+				arr = append(arr, s)
+			}
+		}
+		return arr
+	}
+	return nil
+}
+
+func splitKeywords(str string) []string {
+	var arr []string
+	for _, e := range strings.FieldsFunc(str, func(r rune) bool {
+		return r == ',' || r == '，' || r == '、'
+	}) {
+		if e = strings.TrimSpace(e); e != "" {
+			arr = append(arr, e)
+		}
+	}
+	return arr
+}
+
+func uniqueStrings(arr []string) []string {
+	seen := make(map[string]bool)
+	var result []string
+	for _, e := range arr {
+		// 關鍵字以逗號串接儲存，移除其中的逗號
+		e = strings.TrimSpace(strings.ReplaceAll(e, ",", " "))
+		if e == "" || seen[strings.ToLower(e)] {
+			continue
+		}
+		seen[strings.ToLower(e)] = true
+		result = append(result, e)
+	}
+	return result
+}
+
+func firstString(values ...string) string {
+	for _, v := range values {
+		if v = strings.TrimSpace(v); v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+func parseTime(str string) time.Time {
+	str = strings.TrimSpace(str)
+	if str == "" {
+		return time.Time{}
+	}
+	// 沒有時區的時間視為本地時間，而不是 UTC
+	for _, layout := range dateLayouts {
+		if t, err := time.ParseInLocation(layout, str, time.Local); err == nil {
+			return t.UTC()
+		}
+	}
+	return time.Time{}
+}
@@ -0,0 +1,34 @@
+package util
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseTime(t *testing.T) {
+	local := time.Local
+	time.Local = time.FixedZone("CST", 8*60*60)
+	defer func() { time.Local = local }()
+
+	tests := []struct {
+		in   string
+		want time.Time
+	}{
+		{"2025-08-01T10:32:00+08:00", time.Date(2025, 8, 1, 2, 32, 0, 0, time.UTC)},
+		{"2025-08-01T02:32:00Z", time.Date(2025, 8, 1, 2, 32, 0, 0, time.UTC)},
+		{"2025-08-01 10:32", time.Date(2025, 8, 1, 2, 32, 0, 0, time.UTC)},
+		{"2025/08/01 10:32:00", time.Date(2025, 8, 1, 2, 32, 0, 0, time.UTC)},
+		{"2025-08-01T10:32:00", time.Date(2025, 8, 1, 2, 32, 0, 0, time.UTC)},
+		{"2025-08-01", time.Date(2025, 7, 31, 16, 0, 0, 0, time.UTC)},
+		{"Fri, 01 Aug 2025 10:32:00 +0800", time.Date(2025, 8, 1, 2, 32, 0, 0, time.UTC)},
+		{"", time.Time{}},
+		{"yesterday", time.Time{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			if got := parseTime(tt.in); !got.Equal(tt.want) {
+				t.Errorf("parseTime(%q) = %v, want %v", tt.in, got, tt.want)
+			}
+		})
+	}
+}
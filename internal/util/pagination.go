@@ -0,0 +1,174 @@
+package util
+
+import (
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// 偵測分頁文章的下一頁：rel="next"、分頁列中的「下一頁」連結，
+// 或只差在頁碼參數（?page=2、/page/2）的連結
+
+var (
+	// 以完整的 class／id 名稱比對，避免 page-content、next-post 之類的誤判
+	paginationRegex = regexp.MustCompile(`(?i)(?:^|\s)(?:pagination|pager|pages|page-nav|pagenav|paging|page-links|page-numbers)(?:\s|$)`)
+	nextClassRegex  = regexp.MustCompile(`(?i)(?:^|\s)(?:next|next-page|nextpage|page-next|pagination-next)(?:\s|$)`)
+	pagePathRegex   = regexp.MustCompile(`/page/(\d+)/?$`)
+	// 不含 p：WordPress 以 ?p= 表示文章編號
+	pageParams = []string{"page", "pg", "pn"}
+	nextTexts  = map[string]bool{
+		"下一頁": true, "下頁": true, "下一页": true, "次へ": true, "다음": true,
+		"next": true, "next page": true, "»": true, "›": true, ">": true,
+	}
+)
+
+// 找不到下一頁時回傳空字串，需在移除分頁列之前呼叫
+func nextPage(doc *goquery.Document, pageURL string) string {
+	base, err := url.Parse(pageURL)
+	if err != nil {
+		return ""
+	}
+
+	resolve := func(href string) string {
+		u, err := base.Parse(strings.TrimSpace(href))
+		if err != nil || u.Host != base.Host || (u.Scheme != "http" && u.Scheme != "https") {
+			return ""
+		}
+		u.Fragment = ""
+		if u.String() == base.String() {
+			return ""
+		}
+		return u.String()
+	}
+
+	// 上一篇／下一篇文章的連結也可能標成 rel="next"，只接受同一篇文章或頁碼較大的網址
+	current := pageNumber(base)
+	later := func(href string) string {
+		candidate := resolve(href)
+		if candidate == "" {
+			return ""
+		}
+		u, _ := url.Parse(candidate)
+		if !samePage(base, u) && pageNumber(u) <= current {
+			return ""
+		}
+		return candidate
+	}
+
+	// 1. rel="next"
+	next := ""
+	doc.Find("link[rel~='next'], a[rel~='next']").EachWithBreak(func(i int, s *goquery.Selection) bool {
+		href, _ := s.Attr("href")
+		next = later(href)
+		return next == ""
+	})
+	if next != "" {
+		return next
+	}
+
+	// 2. 分頁列中的下一頁連結
+	doc.Find("a[href]").EachWithBreak(func(i int, s *goquery.Selection) bool {
+		text := strings.ToLower(strings.Trim(strings.TrimSpace(s.Text()), "→»› "))
+		class, _ := s.Attr("class")
+		if !nextTexts[text] && !nextTexts[strings.TrimSpace(s.Text())] && !nextClassRegex.MatchString(class) {
+			return true
+		}
+		if !inPagination(s) {
+			return true
+		}
+		href, _ := s.Attr("href")
+		next = later(href)
+		return next == ""
+	})
+	if next != "" {
+		return next
+	}
+
+	// 3. 頁碼加一的連結
+	doc.Find("a[href]").EachWithBreak(func(i int, s *goquery.Selection) bool {
+		href, _ := s.Attr("href")
+		candidate := resolve(href)
+		if candidate == "" {
+			return true
+		}
+		u, _ := url.Parse(candidate)
+		if samePage(base, u) && pageNumber(u) == current+1 {
+			next = candidate
+			return false
+		}
+		return true
+	})
+	return next
+}
+
+func inPagination(s *goquery.Selection) bool {
+	for i, node := 0, s; i < 4 && node.Length() > 0; i, node = i+1, node.Parent() {
+		if paginationRegex.MatchString(matchString(node)) {
+			return true
+		}
+	}
+	return false
+}
+
+func pageNumber(u *url.URL) int {
+	query := u.Query()
+	for _, key := range pageParams {
+		if n, err := strconv.Atoi(query.Get(key)); err == nil {
+			return n
+		}
+	}
+	if m := pagePathRegex.FindStringSubmatch(u.Path); m != nil {
+		n, _ := strconv.Atoi(m[1])
+		return n
+	}
+	return 1
+}
+
+// 除了頁碼以外的路徑與參數是否相同
+func samePage(a, b *url.URL) bool {
+	strip := func(u *url.URL) string {
+		query := u.Query()
+		for _, key := range pageParams {
+			query.Del(key)
+		}
+		path := strings.TrimSuffix(pagePathRegex.ReplaceAllString(u.Path, ""), "/")
+		return u.Host + path + "?" + query.Encode()
+	}
+	return strip(a) == strip(b)
+}
+
+// 合併各頁內容，只移除後續頁面開頭重複出現的標題、作者列等區塊，
+// 正文中合理重複的段落保留
+func stitch(pages []string) string {
+	seen := make(map[string]bool)
+	var blocks []string
+
+	for i, page := range pages {
+		leading := i > 0
+		for _, block := range strings.Split(page, "\n\n") {
+			key := strings.TrimSpace(block)
+			if key == "" {
+				continue
+			}
+			if leading && seen[key] && isHeaderBlock(key) {
+				continue
+			}
+			leading = false
+			seen[key] = true
+			blocks = append(blocks, block)
+		}
+	}
+	return strings.Join(blocks, "\n\n")
+}
+
+// 標題或不成句的短行（作者、日期列）
+func isHeaderBlock(block string) bool {
+	if strings.HasPrefix(block, "#") {
+		return true
+	}
+	return !strings.Contains(block, "\n") && utf8.RuneCountInString(block) <= 80 && !sentenceRegex.MatchString(block)
+}
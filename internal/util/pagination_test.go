@@ -0,0 +1,111 @@
+package util
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+func TestStitch(t *testing.T) {
+	tests := []struct {
+		name  string
+		pages []string
+		want  string
+	}{
+		{
+			name:  "single page",
+			pages: []string{"# Title\n\nFirst paragraph."},
+			want:  "# Title\n\nFirst paragraph.",
+		},
+		{
+			name: "repeated header and byline",
+			pages: []string{
+				"# Title\n\nBy Reporter\n\nFirst paragraph.",
+				"# Title\n\nBy Reporter\n\nSecond paragraph.",
+			},
+			want: "# Title\n\nBy Reporter\n\nFirst paragraph.\n\nSecond paragraph.",
+		},
+		{
+			name: "repeated paragraph kept",
+			pages: []string{
+				"# Title\n\nWe will rebuild.\n\nFirst paragraph.",
+				"# Title\n\nSecond paragraph.\n\nWe will rebuild.",
+			},
+			want: "# Title\n\nWe will rebuild.\n\nFirst paragraph.\n\nSecond paragraph.\n\nWe will rebuild.",
+		},
+		{
+			name: "repeated heading later in page kept",
+			pages: []string{
+				"## Background\n\nFirst paragraph.",
+				"Second paragraph.\n\n## Background",
+			},
+			want: "## Background\n\nFirst paragraph.\n\nSecond paragraph.\n\n## Background",
+		},
+		{
+			name: "repeated sentence at top kept",
+			pages: []string{
+				"# Title\n\nIt rained.",
+				"# Title\n\nIt rained.\n\nThen it stopped.",
+			},
+			want: "# Title\n\nIt rained.\n\nIt rained.\n\nThen it stopped.",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := stitch(tt.pages); got != tt.want {
+				t.Errorf("got\n%q\nwant\n%q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNextPage(t *testing.T) {
+	tests := []struct {
+		name string
+		url  string
+		page string
+		want string
+	}{
+		{
+			name: "rel next to second page",
+			url:  "https://example.com/news/123",
+			page: `<head><link rel="next" href="/news/123?page=2"></head>`,
+			want: "https://example.com/news/123?page=2",
+		},
+		{
+			name: "next link in pagination",
+			url:  "https://example.com/blog/post/page/2/",
+			page: `<div class="pagination"><a href="/blog/post/">1</a><a href="/blog/post/page/3/">下一頁</a></div>`,
+			want: "https://example.com/blog/post/page/3/",
+		},
+		{
+			name: "single post with post navigation",
+			url:  "https://example.com/2025/06/17/some-post/",
+			page: `<head><link rel="next" href="/2025/06/18/other-post/"></head>
+<div class="page-content"><p>Body</p>
+<nav class="navigation post-navigation"><div class="nav-links">
+<div class="nav-previous"><a href="/2025/06/16/older-post/" rel="prev">Previous post</a></div>
+<div class="nav-next"><a class="next-post" href="/2025/06/18/other-post/" rel="next">Next</a></div>
+</div></nav></div>`,
+			want: "",
+		},
+		{
+			name: "wordpress post id is not a page number",
+			url:  "https://example.com/?p=412",
+			page: `<a rel="next" href="/?p=413">Next post</a>`,
+			want: "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := goquery.NewDocumentFromReader(strings.NewReader(tt.page))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := nextPage(doc, tt.url); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,96 @@
+package util
+
+import (
+	"strings"
+	"unicode"
+
+	"rss-reader/internal/model"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// 付費牆與 cookie 同意頁的常見元素，需在移除 script 與覆蓋層之前檢查
+var (
+	paywallSelector = strings.Join([]string{
+		".paywall", "#paywall", "[class*='paywall']", "[id*='paywall']",
+		".tp-modal", ".tp-backdrop", "[class*='piano-']", ".meteredContent", ".regwall",
+		"[class*='subscriber-only']", "[class*='premium-content']", "[class*='subscribe-wall']",
+	}, ", ")
+	consentSelector = strings.Join([]string{
+		"#onetrust-consent-sdk", "#onetrust-banner-sdk", ".fc-consent-root", "#didomi-host",
+		".qc-cmp2-container", "[id^='sp_message_container']", "#CybotCookiebotDialog",
+		".cookie-consent", "[class*='consent-banner']", "[class*='gdpr']",
+	}, ", ")
+
+	// 只用完整的提示語，避免內文提到「付費」就被誤判
+	paywallMarkers = []string{
+		"subscribe to continue", "subscribe to read", "subscribers only", "only available to subscribers",
+		"already a subscriber", "to continue reading", "become a member to read", "sign in to continue",
+		"訂閱以繼續閱讀", "訂閱後繼續閱讀", "付費會員才能閱讀", "付費會員才可閱讀", "本文為付費文章", "訂戶專屬內容", "會員專屬內容",
+		"登入後繼續閱讀", "订阅后继续阅读", "付费会员才能阅读", "本文为付费文章",
+	}
+	consentMarkers = []string{
+		"we value your privacy", "accept all cookies", "accept cookies", "cookie consent",
+		"manage your privacy", "your privacy choices", "consent to the use of cookies",
+		"同意使用 cookie", "隱私權設定", "接受所有 cookie",
+	}
+)
+
+// 頁面上的付費牆或同意頁跡象
+type wall struct {
+	paywall bool
+	consent bool
+	notFree bool
+}
+
+func detectWall(doc *goquery.Document, meta *metadata) wall {
+	return wall{
+		paywall: doc.Find(paywallSelector).Length() > 0,
+		consent: doc.Find(consentSelector).Length() > 0,
+		notFree: meta.NotFree,
+	}
+}
+
+// 依頁面跡象與擷取結果判斷是否被擋，回傳擷取狀態；未被擋時回傳空字串。
+// 付費牆網站常在 HTML 中保留全文，因此只有正文看起來不完整時才視為被擋
+func (w wall) status(content string, wordCount int, score float64) string {
+	lower := strings.ToLower(content)
+	incomplete := score < minConfidence || truncated(content)
+
+	switch {
+	case wordCount < 300 && containsAny(lower, paywallMarkers):
+		return model.StatusPaywalled
+	case (w.notFree || w.paywall) && incomplete:
+		return model.StatusPaywalled
+	case score < minConfidence && (w.consent || containsAny(lower, consentMarkers)):
+		return model.StatusConsent
+	}
+	return ""
+}
+
+func containsAny(str string, markers []string) bool {
+	for _, marker := range markers {
+		if strings.Contains(str, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+// 正文以刪節號結尾或最後一句沒有結束標點，視為被截斷
+func truncated(content string) bool {
+	content = strings.TrimSpace(content)
+	if content == "" {
+		return true
+	}
+	if strings.HasSuffix(content, "...") || strings.HasSuffix(content, "…") {
+		return true
+	}
+
+	last := []rune(content)[len([]rune(content))-1]
+	if strings.ContainsRune(".!?。！？」』\"'”’)）*`|", last) {
+		return false
+	}
+	// 標題或列表等非文字結尾不判斷
+	return unicode.IsLetter(last) || unicode.IsDigit(last) || last == ',' || last == '，'
+}
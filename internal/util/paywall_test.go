@@ -0,0 +1,59 @@
+package util
+
+import (
+	"testing"
+
+	"rss-reader/internal/model"
+)
+
+func TestWallStatus(t *testing.T) {
+	tests := []struct {
+		name    string
+		wall    wall
+		content string
+		want    string
+	}{
+		{
+			name:    "mentions paid content",
+			content: "政府研擬對付費串流平台課稅，業者表示將與主管機關溝通。",
+			want:    "",
+		},
+		{
+			name:    "mentions paid members",
+			content: "該平台付費會員人數突破百萬，較去年成長三成。",
+			want:    "",
+		},
+		{
+			name:    "paywall prompt",
+			content: "颱風逼近，北部今起嚴防豪雨。\n\n付費會員才能閱讀全文，立即訂閱。",
+			want:    model.StatusPaywalled,
+		},
+		{
+			name:    "english paywall prompt",
+			content: "The budget was approved on Monday.\n\nSubscribe to continue reading.",
+			want:    model.StatusPaywalled,
+		},
+		{
+			name:    "paywall element with truncated text",
+			wall:    wall{paywall: true},
+			content: "The budget was approved on Monday and…",
+			want:    model.StatusPaywalled,
+		},
+		{
+			name:    "consent page",
+			wall:    wall{consent: true},
+			content: "We value your privacy",
+			want:    model.StatusConsent,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			words, cjk := countWords(tt.content)
+			wordCount := words + cjk
+			score := confidence(tt.content, wordCount)
+			if got := tt.wall.status(tt.content, wordCount, score); got != tt.want {
+				t.Errorf("status = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
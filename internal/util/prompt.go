@@ -0,0 +1,264 @@
+package util
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"text/template"
+	"text/template/parse"
+	"time"
+
+	"rss-reader/internal/config"
+)
+
+// 繁體中文範本的外框：系統資訊、指令與前次概要
+const zhFrame = `=== 系統資訊 ===
+當前時間：{{.Now.Format "2006年01月02日 15:04:05"}}
+作業系統與執行環境：{{.Platform}}
+
+%s
+
+
+=== 前次概要 ===
+{{.Previous}}`
+
+const enPrompt = `=== System ===
+Current time: {{.Now.Format "Monday, 2 January 2006 15:04"}}
+Platform: {{.Platform}}
+Articles in this run: {{len .Articles}}
+
+=== Instructions ===
+You are a professional news digest editor. From the articles the user sends (title, date, full content), extract the key points, write a structured digest for today and analyse the trends.
+
+=== Output format ===
+Write in English, in this order:
+
+## Top Stories
+- International affairs, government policy, major social events
+- Keep the details instead of over-compressing
+- Note when it happened and how much impact it has
+
+## Technology & Finance
+- Technology trends, market moves, economic indicators
+- Focus on new technology and investment opportunities
+- Note the affected markets or industries
+
+## Everyday Life
+- Weather, traffic, consumer news
+- Health, education and culture
+
+## Trends
+- Compare with the previous digest and note what changed
+- Identify ongoing and emerging topics
+- Anticipate likely developments
+
+=== Principles ===
+1. Keep as much of the previous digest as possible (items within 24 hours and major news)
+2. Order by importance and timeliness
+3. Fill in details from related articles
+4. Keep figures, dates and names
+5. Note how reliable each source is
+6. Highlight what differs from the previous digest
+{{- if .Previous}}
+
+
+=== Previous digest ===
+{{.Previous}}
+{{- end}}`
+
+// 分批整理（batch）與單篇文章概要（article）的指令，以 define 附在範本後，
+// 讓這兩個步驟的輸出語言與範本一致
+const zhBlocks = `
+
+{{define "batch"}}你是專業的新聞編輯。以下是部分新聞，請用繁體中文逐則條列重點，
+保留數據、時間、人名與來源等關鍵細節，供之後合併為完整的本日概要。不要加入分類標題或額外說明。{{end}}
+
+{{define "article"}}你是專業的新聞編輯。請用繁體中文，以 3 到 5 個條列重點概述使用者提供的新聞。
+每點一句，以「- 」開頭，保留數據、時間、人名等關鍵細節，不要加入標題或額外說明。{{end}}`
+
+const enBlocks = `
+
+{{define "batch"}}You are a professional news editor. The user sends part of today's articles. List the key points of each article in English,
+keeping figures, dates, names and sources, so the notes can be merged into the full digest later. Do not add section headings or commentary.{{end}}
+
+{{define "article"}}You are a professional news editor. Summarize the article the user sends in 3 to 5 bullet points, in English.
+One sentence per point, each starting with "- ". Keep figures, dates and names. Do not add a title or commentary.{{end}}`
+
+// 內建範本，prompts 目錄中同名的檔案優先
+var builtinPrompts = map[string]string{
+	"zh-TW": fmt.Sprintf(zhFrame, defaultPrompt) + zhBlocks,
+	"en":    enPrompt + enBlocks,
+}
+
+// 舊版 summary.prompt 使用的範本，指令內容可能含有 {{ 或 %，因此以資料帶入
+var legacyPrompt = fmt.Sprintf(zhFrame, "{{.Instructions}}")
+
+// 自訂範本沒有定義 batch 或 article 時使用的內建範本
+const fallbackPrompt = "zh-TW"
+
+// 範本可使用的變數；文章內容另以訊息送出，這裡只有標題等資訊
+type PromptData struct {
+	Now      time.Time
+	Platform string
+	Previous string
+	Articles []PromptArticle
+	// 舊版 summary.prompt 的指令，以資料帶入而不當作範本解析
+	Instructions string
+}
+
+type PromptArticle struct {
+	Title       string
+	Source      string
+	URL         string
+	Language    string
+	PublishedAt time.Time
+}
+
+type PromptTemplate struct {
+	Name string
+	// 內建範本沒有檔案時為空字串
+	Path    string
+	Builtin bool
+}
+
+func newPromptData(previous string, articles []PromptArticle) PromptData {
+	return PromptData{
+		Now:      time.Now(),
+		Platform: runtime.GOOS + "/" + runtime.GOARCH,
+		Previous: previous,
+		Articles: articles,
+	}
+}
+
+// 列出內建範本與 prompts 目錄中的 .tmpl 檔
+func ListPrompts() ([]PromptTemplate, error) {
+	found := make(map[string]*PromptTemplate)
+	for name := range builtinPrompts {
+		found[name] = &PromptTemplate{Name: name, Builtin: true}
+	}
+
+	entries, err := os.ReadDir(config.PromptsDir())
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+	for _, entry := range entries {
+		name, ok := strings.CutSuffix(entry.Name(), ".tmpl")
+		if !ok || entry.IsDir() {
+			continue
+		}
+		if found[name] == nil {
+			found[name] = &PromptTemplate{Name: name}
+		}
+		found[name].Path = filepath.Join(config.PromptsDir(), entry.Name())
+	}
+
+	list := make([]PromptTemplate, 0, len(found))
+	for _, t := range found {
+		list = append(list, *t)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Name < list[j].Name
+	})
+	return list, nil
+}
+
+// 讀取範本內容，檔案不存在時使用內建範本
+func LoadPrompt(name string) (string, error) {
+	if err := validPromptName(name); err != nil {
+		return "", err
+	}
+	data, err := os.ReadFile(promptPath(name))
+	if err == nil {
+		return string(data), nil
+	}
+	if !errors.Is(err, os.ErrNotExist) {
+		return "", err
+	}
+	if text, ok := builtinPrompts[name]; ok {
+		return text, nil
+	}
+	return "", fmt.Errorf("prompt template %q not found in %s", name, config.PromptsDir())
+}
+
+// 取得可編輯的範本檔路徑，尚無檔案時以內建範本建立；
+// 新範本複製 from（目前使用的範本）的內容，讀取失敗時改用 zh-TW
+func PromptFile(name, from string) (string, error) {
+	if err := validPromptName(name); err != nil {
+		return "", err
+	}
+	path := promptPath(name)
+	if _, err := os.Stat(path); err == nil {
+		return path, nil
+	}
+
+	text, ok := builtinPrompts[name]
+	if !ok {
+		var err error
+		if text, err = LoadPrompt(from); err != nil {
+			text = builtinPrompts[fallbackPrompt]
+		}
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return "", err
+	}
+	if err := os.WriteFile(path, []byte(text), 0644); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+func RenderPrompt(name, text string, data PromptData) (string, error) {
+	return renderPrompt(name, text, "", data)
+}
+
+// 產生範本中的 batch 或 article 指令，block 為空字串時產生系統提示詞
+func renderPrompt(name, text, block string, data PromptData) (string, error) {
+	tmpl, err := parsePrompt(name, text)
+	if err != nil {
+		return "", err
+	}
+	if block != "" {
+		if tmpl = tmpl.Lookup(block); tmpl == nil {
+			return "", fmt.Errorf("prompt template %s has no %q block", name, block)
+		}
+	}
+	var b strings.Builder
+	if err := tmpl.Execute(&b, data); err != nil {
+		return "", fmt.Errorf("failed to render prompt template %s: %w", name, err)
+	}
+	return strings.TrimSpace(b.String()), nil
+}
+
+// 先載入同名（或預設）內建範本的 batch、article 區塊，再以範本內容覆蓋
+func parsePrompt(name, text string) (*template.Template, error) {
+	base, ok := builtinPrompts[name]
+	if !ok {
+		base = builtinPrompts[fallbackPrompt]
+	}
+	tmpl := template.Must(template.New(name).Option("missingkey=error").Parse(base))
+	if _, err := tmpl.Parse(text); err != nil {
+		return nil, fmt.Errorf("invalid prompt template %s: %w", name, err)
+	}
+	// 空白的本文不會取代 zh-TW 的本文，新範本只有 define 區塊時會產生中文提示詞
+	if !ok {
+		if own, err := template.New(name).Parse(text); err != nil || own.Tree == nil || parse.IsEmptyTree(own.Tree.Root) {
+			return nil, fmt.Errorf("prompt template %s has no text outside its define blocks", name)
+		}
+	}
+	return tmpl, nil
+}
+
+func promptPath(name string) string {
+	return filepath.Join(config.PromptsDir(), name+".tmpl")
+}
+
+func validPromptName(name string) error {
+	if name == "" || strings.HasPrefix(name, ".") || strings.ContainsAny(name, `/\`) {
+		return fmt.Errorf("invalid prompt template name %q", name)
+	}
+	return nil
+}
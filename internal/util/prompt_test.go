@@ -0,0 +1,134 @@
+package util
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRenderPrompt(t *testing.T) {
+	data := PromptData{
+		Now:      time.Date(2025, 8, 1, 10, 32, 0, 0, time.UTC),
+		Platform: "linux/amd64",
+		Previous: "## 前次 {{.Now}} 100%",
+	}
+	custom := `Digest for {{.Now.Format "2006-01-02"}}.`
+	customBlocks := custom + `{{define "batch"}}Notes in French.{{end}}{{define "article"}}Bullets in French.{{end}}`
+
+	tests := []struct {
+		name     string
+		template string
+		text     string
+		block    string
+		data     PromptData
+		contains []string
+		excludes []string
+	}{
+		{
+			name:     "zh-TW digest",
+			template: "zh-TW",
+			text:     builtinPrompts["zh-TW"],
+			contains: []string{"2025年08月01日", "## 重大要聞", "## 前次 {{.Now}} 100%"},
+			excludes: []string{"{{define", "逐則條列重點"},
+		},
+		{
+			name:     "en batch",
+			template: "en",
+			text:     builtinPrompts["en"],
+			block:    "batch",
+			contains: []string{"in English"},
+			excludes: []string{"繁體中文"},
+		},
+		{
+			name:     "en article",
+			template: "en",
+			text:     builtinPrompts["en"],
+			block:    "article",
+			contains: []string{"3 to 5 bullet points, in English"},
+			excludes: []string{"繁體中文"},
+		},
+		{
+			name:     "zh-TW article",
+			template: "zh-TW",
+			text:     builtinPrompts["zh-TW"],
+			block:    "article",
+			contains: []string{"繁體中文"},
+		},
+		{
+			name:     "override keeps built-in blocks",
+			template: "en",
+			text:     custom,
+			block:    "batch",
+			contains: []string{"in English"},
+		},
+		{
+			name:     "custom template falls back to default blocks",
+			template: "custom",
+			text:     custom,
+			block:    "article",
+			contains: []string{"繁體中文"},
+		},
+		{
+			name:     "custom root",
+			template: "custom",
+			text:     customBlocks,
+			contains: []string{"Digest for 2025-08-01."},
+			excludes: []string{"French", "重大要聞"},
+		},
+		{
+			name:     "custom blocks",
+			template: "custom",
+			text:     customBlocks,
+			block:    "batch",
+			contains: []string{"Notes in French."},
+		},
+		{
+			name:     "legacy instructions are not parsed",
+			template: "zh-TW",
+			text:     legacyPrompt,
+			data:     PromptData{Instructions: "用 {{ 與 %s 100% 整理"},
+			contains: []string{"=== 系統資訊 ===", "用 {{ 與 %s 100% 整理"},
+			excludes: []string{"## 重大要聞", "%!"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := data
+			if tt.data.Instructions != "" {
+				d.Instructions = tt.data.Instructions
+			}
+			got, err := renderPrompt(tt.template, tt.text, tt.block, d)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, s := range tt.contains {
+				if !strings.Contains(got, s) {
+					t.Errorf("missing %q in\n%s", s, got)
+				}
+			}
+			for _, s := range tt.excludes {
+				if strings.Contains(got, s) {
+					t.Errorf("unexpected %q in\n%s", s, got)
+				}
+			}
+		})
+	}
+}
+
+func TestRenderPromptErrors(t *testing.T) {
+	tests := []struct {
+		name, text, err string
+	}{
+		{"syntax", "{{.Now", "invalid prompt template"},
+		{"unknown field", "{{.Missing}}", "failed to render"},
+		{"empty body", "\n{{define \"batch\"}}Take notes.{{end}}\n", "no text outside"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := RenderPrompt("custom", tt.text, newPromptData("", nil))
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("err = %v, want %q", err, tt.err)
+			}
+		})
+	}
+}
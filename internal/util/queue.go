@@ -0,0 +1,100 @@
+package util
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"rss-reader/internal/database"
+)
+
+// 重試間隔上限
+const maxBackoff = time.Minute
+
+// 限制同一網站的請求頻率，不同網站可同時擷取
+type hostLimiter struct {
+	mu       sync.Mutex
+	next     map[string]time.Time
+	interval time.Duration
+}
+
+func newHostLimiter(interval time.Duration) *hostLimiter {
+	return &hostLimiter{
+		next:     make(map[string]time.Time),
+		interval: interval,
+	}
+}
+
+// 預約該網站的下一個請求時段並等待
+func (l *hostLimiter) wait(ctx context.Context, host string) error {
+	l.mu.Lock()
+	now := time.Now()
+	slot := l.next[host]
+	if slot.Before(now) {
+		slot = now
+	}
+	l.next[host] = slot.Add(l.interval)
+	l.mu.Unlock()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(time.Until(slot)):
+		return nil
+	}
+}
+
+// 依網站輪流排列，避免所有 worker 同時等待同一個網站
+func interleave(items []database.QueueItem) []database.QueueItem {
+	var hosts []string
+	groups := make(map[string][]database.QueueItem)
+	for _, item := range items {
+		host := hostname(item.News.URL)
+		if _, ok := groups[host]; !ok {
+			hosts = append(hosts, host)
+		}
+		groups[host] = append(groups[host], item)
+	}
+
+	result := make([]database.QueueItem, 0, len(items))
+	for len(result) < len(items) {
+		for _, host := range hosts {
+			if group := groups[host]; len(group) > 0 {
+				result = append(result, group[0])
+				groups[host] = group[1:]
+			}
+		}
+	}
+	return result
+}
+
+func hostname(link string) string {
+	u, err := url.Parse(link)
+	if err != nil {
+		return ""
+	}
+	return u.Hostname()
+}
+
+// 連線錯誤、逾時、429 與 5xx 視為暫時性錯誤
+func transient(err error) bool {
+	var status *StatusError
+	if errors.As(err, &status) {
+		return status.Code == http.StatusTooManyRequests || status.Code >= http.StatusInternalServerError
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
+
+// 第 n 次失敗後的等待時間：1s、2s、4s...
+func backoff(attempt int) time.Duration {
+	d := time.Second << attempt
+	if d <= 0 || d > maxBackoff {
+		return maxBackoff
+	}
+	return d
+}
@@ -0,0 +1,311 @@
+package util
+
+import (
+	"math"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/PuerkitoBio/goquery"
+	"golang.org/x/net/html"
+)
+
+// 以 Readability 的方式為節點評分：段落依長度與標點加分，分數向上傳遞給父層，
+// 再依 class/id 權重與連結密度調整，最後合併分數相近的兄弟節點
+
+var (
+	unlikelyRegex = regexp.MustCompile(`(?i)ad-break|agegate|banner|breadcrumb|combx|comment|community|consent|cookie|cover-wrap|disqus|extra|footer|gdpr|header|legends|menu|modal|newsletter|pager|pagination|popup|recommend|related|remark|replies|rss|share|shoutbox|sidebar|skyscraper|social|sponsor|subscribe|supplemental|toolbar`)
+	maybeRegex    = regexp.MustCompile(`(?i)and|article|body|column|content|main|shadow`)
+	positiveRegex = regexp.MustCompile(`(?i)article|body|content|entry|hentry|h-entry|main|page|post|text|blog|story`)
+	negativeRegex = regexp.MustCompile(`(?i)-ad-|hidden|^hid$|\bhid\b|banner|combx|comment|com-|consent|contact|cookie|foot|footnote|gdpr|masthead|media|meta|newsletter|outbrain|promo|recommend|related|scroll|share|shoutbox|sidebar|skyscraper|sponsor|shopping|subscribe|tags|tool|widget`)
+	commaRegex    = regexp.MustCompile(`[,，、。；;]`)
+	sentenceRegex = regexp.MustCompile(`[.。!?！？]\s*$`)
+)
+
+// 段落至少需要的字數，太短的文字不計分
+const paragraphMinLength = 25
+
+// 這些子元素存在時 div 視為容器，否則視為段落
+var blockSelector = "address, article, blockquote, dl, div, figure, form, h1, h2, h3, h4, h5, h6, img, ol, p, pre, section, table, ul"
+
+type readability struct {
+	doc    *goquery.Document
+	scores map[*html.Node]float64
+}
+
+func newReadability(doc *goquery.Document) *readability {
+	return &readability{
+		doc:    doc,
+		scores: make(map[*html.Node]float64),
+	}
+}
+
+// 回傳正文節點（最高分節點與合併的兄弟節點），找不到時回傳 nil
+func (r *readability) parse() []*html.Node {
+	r.removeUnlikely()
+
+	r.doc.Find("p, pre, td, blockquote, div").Each(func(i int, s *goquery.Selection) {
+		if goquery.NodeName(s) == "div" && s.Find(blockSelector).Length() > 0 {
+			return
+		}
+
+		length := textLength(s)
+		if length < paragraphMinLength {
+			return
+		}
+
+		text := s.Text()
+		score := 1 + float64(len(commaRegex.FindAllString(text, -1))) + math.Min(float64(length)/100, 3)
+
+		// 分數傳遞給上三層祖先，越遠權重越低
+		for level, ancestor := range ancestors(s.Nodes[0], 3) {
+			if _, ok := r.scores[ancestor]; !ok {
+				r.scores[ancestor] = initialScore(ancestor)
+			}
+			switch level {
+			case 0:
+				r.scores[ancestor] += score
+			case 1:
+				r.scores[ancestor] += score / 2
+			default:
+				r.scores[ancestor] += score / float64(level*3)
+			}
+		}
+	})
+
+	// 依文件順序挑選，同分時取最先出現的節點，結果不受 map 順序影響
+	var top *html.Node
+	topScore := 0.0
+	r.doc.Find("*").Each(func(i int, s *goquery.Selection) {
+		node := s.Nodes[0]
+		score, ok := r.scores[node]
+		if !ok {
+			return
+		}
+		score *= 1 - linkDensity(s)
+		r.scores[node] = score
+		if top == nil || score > topScore {
+			top = node
+			topScore = score
+		}
+	})
+
+	if top == nil {
+		return nil
+	}
+
+	top, topScore = r.promote(top, topScore)
+	nodes := r.siblings(top, topScore)
+	for _, node := range nodes {
+		r.clean(goquery.NewDocumentFromNode(node).Selection)
+	}
+	return nodes
+}
+
+func (r *readability) removeUnlikely() {
+	r.doc.Find("script, style, noscript, iframe, form, button, svg, nav, aside, footer, .advertisement, .ads, .comment").Remove()
+	r.doc.Find("[role='navigation'], [role='complementary'], [role='dialog'], [role='alertdialog'], [aria-hidden='true']").Remove()
+
+	r.doc.Find("*").Each(func(i int, s *goquery.Selection) {
+		switch goquery.NodeName(s) {
+		case "html", "body", "article", "main", "a":
+			return
+		}
+		match := matchString(s)
+		if match == "" {
+			return
+		}
+		if unlikelyRegex.MatchString(match) && !maybeRegex.MatchString(match) {
+			s.Remove()
+		}
+	})
+}
+
+// 正文被小標切成多個同分區塊時，改用包含其他高分候選的最近祖先
+func (r *readability) promote(top *html.Node, topScore float64) (*html.Node, float64) {
+	var alternatives []*html.Node
+	for node, score := range r.scores {
+		// 最高分節點的祖先與子孫不算其他候選
+		if node == top || contains(node, top) || contains(top, node) {
+			continue
+		}
+		if score >= topScore*0.75 {
+			alternatives = append(alternatives, node)
+		}
+	}
+	if len(alternatives) == 0 {
+		return top, topScore
+	}
+
+	for ancestor := top.Parent; ancestor != nil && ancestor.Type == html.ElementNode; ancestor = ancestor.Parent {
+		if ancestor.Data == "body" || ancestor.Data == "html" {
+			break
+		}
+		for _, node := range alternatives {
+			if contains(ancestor, node) {
+				return ancestor, math.Max(topScore, r.scores[ancestor])
+			}
+		}
+	}
+	return top, topScore
+}
+
+func contains(ancestor, node *html.Node) bool {
+	for parent := node.Parent; parent != nil; parent = parent.Parent {
+		if parent == ancestor {
+			return true
+		}
+	}
+	return false
+}
+
+// 合併與最高分節點同層、分數接近或看起來像正文段落的兄弟節點
+func (r *readability) siblings(top *html.Node, topScore float64) []*html.Node {
+	parent := top.Parent
+	if parent == nil {
+		return []*html.Node{top}
+	}
+
+	threshold := math.Max(10, topScore*0.2)
+	topClass := attr(top, "class")
+
+	var nodes []*html.Node
+	for sibling := parent.FirstChild; sibling != nil; sibling = sibling.NextSibling {
+		if sibling.Type != html.ElementNode {
+			continue
+		}
+		if sibling == top {
+			nodes = append(nodes, sibling)
+			continue
+		}
+
+		bonus := 0.0
+		if topClass != "" && attr(sibling, "class") == topClass {
+			bonus = topScore * 0.2
+		}
+		if score, ok := r.scores[sibling]; ok && score+bonus >= threshold {
+			nodes = append(nodes, sibling)
+			continue
+		}
+
+		if sibling.Data != "p" {
+			continue
+		}
+		s := goquery.NewDocumentFromNode(sibling).Selection
+		length := textLength(s)
+		density := linkDensity(s)
+		if length > 80 && density < 0.25 {
+			nodes = append(nodes, sibling)
+		} else if length > 0 && length <= 80 && density == 0 && sentenceRegex.MatchString(s.Text()) {
+			nodes = append(nodes, sibling)
+		}
+	}
+	return nodes
+}
+
+// 移除正文內的分享列、相關文章等權重為負或連結過多的區塊
+func (r *readability) clean(s *goquery.Selection) {
+	s.Find("div, section, ul, ol, table, figure, h1, h2, h3, h4, h5, h6").Each(func(i int, e *goquery.Selection) {
+		weight := classWeight(e.Nodes[0])
+		if weight < 0 {
+			e.Remove()
+			return
+		}
+
+		// 標題只依權重移除
+		name := goquery.NodeName(e)
+		if len(name) == 2 && name[0] == 'h' {
+			return
+		}
+
+		length := textLength(e)
+		density := linkDensity(e)
+		paragraphs := e.Find("p").Length()
+		items := e.Find("li").Length()
+
+		switch {
+		case density > 0.5:
+			e.Remove()
+		case weight < 25 && density > 0.2 && length < 200:
+			e.Remove()
+		case name != "ul" && name != "ol" && items > paragraphs && density > 0.3:
+			e.Remove()
+		case name == "figure":
+		case length < paragraphMinLength && e.Find("img").Length() == 0 && paragraphs == 0 && name != "table":
+			e.Remove()
+		}
+	})
+}
+
+func initialScore(node *html.Node) float64 {
+	score := float64(classWeight(node))
+	switch node.Data {
+	case "div", "article", "section", "main":
+		score += 5
+	case "pre", "td", "blockquote":
+		score += 3
+	case "address", "ol", "ul", "dl", "dd", "dt", "li", "form":
+		score -= 3
+	case "h1", "h2", "h3", "h4", "h5", "h6", "th":
+		score -= 5
+	}
+	return score
+}
+
+func classWeight(node *html.Node) int {
+	weight := 0
+	for _, value := range []string{attr(node, "class"), attr(node, "id")} {
+		if value == "" {
+			continue
+		}
+		if negativeRegex.MatchString(value) {
+			weight -= 25
+		}
+		if positiveRegex.MatchString(value) {
+			weight += 25
+		}
+	}
+	return weight
+}
+
+func ancestors(node *html.Node, max int) []*html.Node {
+	var arr []*html.Node
+	for parent := node.Parent; parent != nil && parent.Type == html.ElementNode && len(arr) < max; parent = parent.Parent {
+		if parent.Data == "html" {
+			break
+		}
+		arr = append(arr, parent)
+	}
+	return arr
+}
+
+func linkDensity(s *goquery.Selection) float64 {
+	length := textLength(s)
+	if length == 0 {
+		return 0
+	}
+	link := 0
+	s.Find("a").Each(func(i int, a *goquery.Selection) {
+		link += textLength(a)
+	})
+	return float64(link) / float64(length)
+}
+
+func textLength(s *goquery.Selection) int {
+	return utf8.RuneCountInString(strings.Join(strings.Fields(s.Text()), " "))
+}
+
+func matchString(s *goquery.Selection) string {
+	class, _ := s.Attr("class")
+	id, _ := s.Attr("id")
+	return strings.TrimSpace(class + " " + id)
+}
+
+func attr(node *html.Node, key string) string {
+	for _, a := range node.Attr {
+		if a.Key == key {
+			return a.Val
+		}
+	}
+	return ""
+}
@@ -0,0 +1,101 @@
+package util
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// 擷取品質的下限，低於此值代表擷取邏輯退步
+const (
+	minPageF1    = 0.85
+	minAverageF1 = 0.95
+)
+
+func TestExtractCorpus(t *testing.T) {
+	pages, err := filepath.Glob(filepath.Join("..", "..", "testdata", "extract", "*.html"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pages) == 0 {
+		t.Fatal("no pages in testdata/extract")
+	}
+
+	// 不讀取使用者設定目錄中的 rules.toml，結果只取決於通用演算法
+	extractor := &Extractor{client: &http.Client{}}
+	total := 0.0
+	for _, page := range pages {
+		name := strings.TrimSuffix(filepath.Base(page), ".html")
+
+		expected, err := os.ReadFile(strings.TrimSuffix(page, ".html") + ".txt")
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		f, err := os.Open(page)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		extracted, err := extractor.Parse(f, "")
+		f.Close()
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+
+		precision, recall, f1 := ScoreExtraction(extracted.Content, string(expected))
+		total += f1
+		if f1 < minPageF1 {
+			t.Errorf("%s: f1 = %.3f (precision %.3f, recall %.3f), want >= %.2f", name, f1, precision, recall, minPageF1)
+		}
+	}
+
+	if average := total / float64(len(pages)); average < minAverageF1 {
+		t.Errorf("average f1 = %.3f, want >= %.2f", average, minAverageF1)
+	}
+}
+
+func TestReadabilityTieBreak(t *testing.T) {
+	const page = `<html><body>
+<div id="first"><p>Alpha paragraph with enough words to be scored as content, and one comma.</p></div>
+<div id="second"><p>Bravo paragraph with enough words to be scored as content, and one comma.</p></div>
+</body></html>`
+
+	for i := 0; i < 20; i++ {
+		doc, err := goquery.NewDocumentFromReader(strings.NewReader(page))
+		if err != nil {
+			t.Fatal(err)
+		}
+		nodes := newReadability(doc).parse()
+		if len(nodes) == 0 {
+			t.Fatal("no content found")
+		}
+		if id := attr(nodes[0], "id"); id != "first" {
+			t.Fatalf("run %d: top candidate = %q, want %q", i, id, "first")
+		}
+	}
+}
+
+func TestScoreExtraction(t *testing.T) {
+	tests := []struct {
+		name      string
+		got, want string
+		f1        float64
+	}{
+		{"identical", "the quick fox", "the quick fox", 1},
+		{"disjoint", "alpha beta", "gamma delta", 0},
+		{"empty", "", "anything", 0},
+		{"case insensitive", "The Quick Fox", "the quick fox", 1},
+		{"cjk by character", "颱風逼近", "颱風", 2 * 0.5 * 1 / 1.5},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, f1 := ScoreExtraction(tt.got, tt.want)
+			if diff := f1 - tt.f1; diff > 1e-9 || diff < -1e-9 {
+				t.Errorf("f1 = %v, want %v", f1, tt.f1)
+			}
+		})
+	}
+}
@@ -0,0 +1,123 @@
+package util
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+)
+
+// 針對特定網站的擷取規則，Host 支援 glob（如 *.udn.com），
+// 不含萬用字元時同時符合子網域
+type Rule struct {
+	Host    string   `toml:"host"`
+	Content string   `toml:"content,omitempty"`
+	Title   string   `toml:"title,omitempty"`
+	Author  string   `toml:"author,omitempty"`
+	Strip   []string `toml:"strip,omitempty"`
+}
+
+type rulesFile struct {
+	Rule []Rule `toml:"rule"`
+}
+
+func LoadRules(filePath string) ([]Rule, error) {
+	if filePath == "" {
+		return nil, nil
+	}
+
+	var file rulesFile
+	if _, err := toml.DecodeFile(filePath, &file); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read rules %s: %w", filePath, err)
+	}
+
+	for i, rule := range file.Rule {
+		if strings.TrimSpace(rule.Host) == "" {
+			return nil, fmt.Errorf("rule %d in %s has no host", i+1, filePath)
+		}
+		if _, err := path.Match(rule.Host, ""); err != nil {
+			return nil, fmt.Errorf("rule %d in %s: invalid host pattern %q", i+1, filePath, rule.Host)
+		}
+	}
+	return file.Rule, nil
+}
+
+// 新增或取代相同 Host 的規則並寫回檔案
+func SaveRule(filePath string, rule Rule) error {
+	if filePath == "" {
+		return errors.New("rules path is not set")
+	}
+
+	rules, err := LoadRules(filePath)
+	if err != nil {
+		return err
+	}
+
+	replaced := false
+	for i := range rules {
+		if strings.EqualFold(rules[i].Host, rule.Host) {
+			rules[i] = rule
+			replaced = true
+		}
+	}
+	if !replaced {
+		rules = append(rules, rule)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
+		return err
+	}
+
+	f, err := os.Create(filePath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	return toml.NewEncoder(f).Encode(rulesFile{Rule: rules})
+}
+
+func (r Rule) Match(host string) bool {
+	host = strings.ToLower(host)
+	pattern := strings.ToLower(strings.TrimSpace(r.Host))
+
+	if strings.ContainsAny(pattern, "*?[") {
+		ok, _ := path.Match(pattern, host)
+		return ok
+	}
+	return host == pattern || strings.HasSuffix(host, "."+pattern)
+}
+
+func (r Rule) String() string {
+	var arr []string
+	for _, e := range [][2]string{{"content", r.Content}, {"title", r.Title}, {"author", r.Author}} {
+		if e[1] != "" {
+			arr = append(arr, fmt.Sprintf("%s=%s", e[0], e[1]))
+		}
+	}
+	if len(r.Strip) > 0 {
+		arr = append(arr, "strip="+strings.Join(r.Strip, ","))
+	}
+	return fmt.Sprintf("%s %s", r.Host, strings.Join(arr, " "))
+}
+
+func findRule(rules []Rule, pageURL string) *Rule {
+	u, err := url.Parse(pageURL)
+	if err != nil || u.Hostname() == "" {
+		return nil
+	}
+	for i := range rules {
+		if rules[i].Match(u.Hostname()) {
+			return &rules[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,138 @@
+package util
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestLoadRules(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    []Rule
+		err     string
+	}{
+		{
+			name: "valid",
+			content: `
+[[rule]]
+host = "*.udn.com"
+content = "#story_body"
+strip = [".social-share", ".story-list__related"]
+
+[[rule]]
+host = "ltn.com.tw"
+title = "h1"
+author = ".author"
+`,
+			want: []Rule{
+				{Host: "*.udn.com", Content: "#story_body", Strip: []string{".social-share", ".story-list__related"}},
+				{Host: "ltn.com.tw", Title: "h1", Author: ".author"},
+			},
+		},
+		{
+			name:    "empty file",
+			content: "",
+			want:    nil,
+		},
+		{
+			name:    "missing host",
+			content: "[[rule]]\ncontent = \"article\"\n",
+			err:     "has no host",
+		},
+		{
+			name:    "invalid pattern",
+			content: "[[rule]]\nhost = \"[udn.com\"\n",
+			err:     "invalid host pattern",
+		},
+		{
+			name:    "invalid toml",
+			content: "[[rule]\nhost = ",
+			err:     "failed to read rules",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filePath := filepath.Join(t.TempDir(), "rules.toml")
+			if err := os.WriteFile(filePath, []byte(tt.content), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			rules, err := LoadRules(filePath)
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("err = %v, want %q", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(rules, tt.want) {
+				t.Errorf("rules = %+v, want %+v", rules, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadRulesMissing(t *testing.T) {
+	for _, filePath := range []string{"", filepath.Join(t.TempDir(), "missing.toml")} {
+		rules, err := LoadRules(filePath)
+		if err != nil || rules != nil {
+			t.Errorf("LoadRules(%q) = %v, %v, want nil, nil", filePath, rules, err)
+		}
+	}
+}
+
+func TestRuleMatch(t *testing.T) {
+	tests := []struct {
+		host    string
+		pattern string
+		want    bool
+	}{
+		{"udn.com", "udn.com", true},
+		{"news.udn.com", "udn.com", true},
+		{"NEWS.UDN.COM", "udn.com", true},
+		{"notudn.com", "udn.com", false},
+		{"udn.com.evil.example", "udn.com", false},
+		{"news.udn.com", "*.udn.com", true},
+		{"udn.com", "*.udn.com", false},
+		{"a.b.udn.com", "*.udn.com", true},
+		{"www1.example.com", "www?.example.com", true},
+		{"example.com", " Example.com ", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.pattern+" "+tt.host, func(t *testing.T) {
+			if got := (Rule{Host: tt.pattern}).Match(tt.host); got != tt.want {
+				t.Errorf("Rule{%q}.Match(%q) = %v, want %v", tt.pattern, tt.host, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFindRule(t *testing.T) {
+	rules := []Rule{{Host: "*.udn.com", Content: "a"}, {Host: "udn.com", Content: "b"}}
+	tests := []struct {
+		url  string
+		want string
+	}{
+		{"https://news.udn.com/news/story/1", "a"},
+		{"https://udn.com/news/story/1", "b"},
+		{"https://example.com/", ""},
+		{"not a url", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			got := ""
+			if rule := findRule(rules, tt.url); rule != nil {
+				got = rule.Content
+			}
+			if got != tt.want {
+				t.Errorf("findRule(%q) = %q, want %q", tt.url, got, tt.want)
+			}
+		})
+	}
+}
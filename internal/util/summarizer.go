@@ -0,0 +1,301 @@
+package util
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"rss-reader/internal/api"
+	"rss-reader/internal/config"
+	"rss-reader/internal/database"
+	"rss-reader/internal/model"
+)
+
+// 預設的概要指令，可由設定檔 summary.prompt 取代
+const defaultPrompt = `=== 指令說明 ===
+你是專業的新聞概要整理助手。請根據輸入的新聞內容（標題、日期、完整內容）提取重點，生成結構化的本日概要並分析趨勢。
+
+=== 輸出格式要求 ===
+使用繁體中文，按以下順序組織內容：
+
+## 重大要聞
+- 國際局勢、政府政策、重大社會事件
+- 保留詳細資訊，避免過度壓縮
+- 標註時間與影響程度
+
+## 科技與金融
+- 科技趨勢、市場動向、經濟指標
+- 重點關注創新技術與投資機會
+- 標註相關股市或產業影響
+
+## 生活資訊
+- 天氣預報、交通狀況、消費資訊
+- 健康醫療、教育文化相關消息
+
+## 趨勢分析
+- 對比前次概要，標註變化趨勢
+- 識別持續發展或新興話題
+- 預測可能後續發展
+
+=== 處理原則 ===
+1. 盡可能地保留舊有概要內容（包含24小時內、重大消息）
+2. 依新聞重要性與時效性排序
+3. 盡可能從相近新聞中補充細節
+4. 保留數據、時間、人名等關鍵細節
+5. 標註消息來源可信度
+6. 突出與前次概要的差異變化`
+
+// 分批整理後仍超過預算時再次合併的最多輪數
+const maxRounds = 3
+
+type Summarizer struct {
+	db *database.SQLite
+}
+
+func NewSummarizer(db *database.SQLite) *Summarizer {
+	return &Summarizer{db: db}
+}
+
+// 依前次概要與新文章產生本日概要，成功後寫回資料庫；
+// 分批時 progress 收到已完成的請求數與總數，onDelta 依序收到最終概要的內容，皆可為 nil
+func (s *Summarizer) Generate(ctx context.Context, news []model.News, progress func(current, total int), onDelta func(string)) (string, error) {
+	ctx = loadAPIKey(ctx, s.db)
+	ctx = api.WithPurpose(ctx, "digest")
+
+	prompt, messages, urls, err := s.prepare(ctx, s.PromptName(ctx), news)
+	if err != nil {
+		return "", err
+	}
+
+	result, err := digest(ctx, prompt, messages, progress, onDelta)
+	if err != nil {
+		return "", err
+	}
+
+	// 每次產生的概要都保留在歷史中
+	err = s.db.AddSummary(ctx, database.Summary{
+		Content:  result,
+		Model:    config.Get().LLM.SmallModel,
+		Articles: urls,
+	})
+	if err != nil {
+		return "", err
+	}
+	return result, nil
+}
+
+// 範本產生的最終概要與分批整理指令
+type digestPrompt struct {
+	system string
+	batch  string
+}
+
+// 以範本 name 產生提示詞，並整理要送出的文章訊息與網址
+func (s *Summarizer) prepare(ctx context.Context, name string, news []model.News) (digestPrompt, []api.Message, []string, error) {
+	summary, _ := s.db.GetKey(ctx, "summary")
+	var messages []api.Message
+	var urls []string
+	var articles []PromptArticle
+
+	add := func(item model.News) {
+		message, ok := articleMessage(item)
+		if !ok {
+			return
+		}
+		messages = append(messages, message)
+		urls = append(urls, item.URL)
+		language := ""
+		if item.Language != nil {
+			language = *item.Language
+		}
+		articles = append(articles, PromptArticle{
+			Title:       item.Title,
+			Source:      item.Source,
+			URL:         item.URL,
+			Language:    language,
+			PublishedAt: item.PublishedAt,
+		})
+	}
+
+	// 沒有前次概要時，補上保留範圍內的文章作為基礎
+	included := make(map[string]bool)
+	if summary == "" {
+		arr, _ := s.db.Get(ctx, config.Hours(config.Get().Retention.Summary))
+		sort.Slice(arr, func(i, j int) bool {
+			return arr[i].PublishedAt.After(arr[j].PublishedAt)
+		})
+
+		for _, item := range arr {
+			included[item.URL] = true
+			add(item)
+		}
+	}
+
+	for _, item := range news {
+		if included[item.URL] {
+			continue
+		}
+		// RSS 列表中的文章沒有擷取結果，以資料庫為準
+		if stored, err := s.db.GetFromURL(ctx, item.URL); err == nil {
+			item.Status = stored.Status
+			item.Language = stored.Language
+		}
+		add(item)
+	}
+
+	text, err := LoadPrompt(name)
+	if err != nil {
+		return digestPrompt{}, nil, nil, err
+	}
+	data := newPromptData(summary, articles)
+	// 舊版 summary.prompt 只取代指令段落
+	if legacy := strings.TrimSpace(config.Get().Summary.Prompt); legacy != "" {
+		text = legacyPrompt
+		data.Instructions = legacy
+	}
+
+	var prompt digestPrompt
+	if prompt.system, err = renderPrompt(name, text, "", data); err != nil {
+		return digestPrompt{}, nil, nil, err
+	}
+	if prompt.batch, err = renderPrompt(name, text, "batch", data); err != nil {
+		return digestPrompt{}, nil, nil, err
+	}
+	return prompt, messages, urls, nil
+}
+
+// 目前使用的範本：prompt use 的選擇優先於設定檔
+func (s *Summarizer) PromptName(ctx context.Context) string {
+	if name, _ := s.db.GetKey(ctx, "prompt"); name != "" {
+		return name
+	}
+	return config.Get().Summary.Template
+}
+
+// 選擇範本，name 為空字串時改回設定檔的 summary.template
+func (s *Summarizer) UsePrompt(ctx context.Context, name string) error {
+	if name != "" {
+		text, err := LoadPrompt(name)
+		if err != nil {
+			return err
+		}
+		if _, err := RenderPrompt(name, text, newPromptData("", nil)); err != nil {
+			return err
+		}
+	}
+	return s.db.SetKey(ctx, "prompt", name)
+}
+
+// 以目前的文章與前次概要預覽範本產生的系統提示詞，回傳提示詞、文章數與估算的 token 數
+func (s *Summarizer) PreviewPrompt(ctx context.Context, name string, news []model.News) (string, int, int, error) {
+	prompt, messages, _, err := s.prepare(ctx, name, news)
+	if err != nil {
+		return "", 0, 0, err
+	}
+	tokens := estimateMessages(append([]api.Message{{Role: "system", Content: prompt.system}}, messages...))
+	return prompt.system, len(messages), tokens, nil
+}
+
+// 超過 summary.budget 時將文章分批整理重點（map），再以完整指令合併為概要（reduce）
+func digest(ctx context.Context, prompt digestPrompt, messages []api.Message, progress func(current, total int), onDelta func(string)) (string, error) {
+	budget := config.Get().Summary.Budget
+	system := api.Message{Role: "system", Content: prompt.system}
+	batch := api.Message{Role: "system", Content: prompt.batch}
+
+	for round := 1; ; round++ {
+		limit := max(budget-estimateMessages([]api.Message{system}), budget/4)
+		if estimateMessages(messages) <= limit || round > maxRounds {
+			break
+		}
+
+		batches := batchMessages(messages, budget-estimateMessages([]api.Message{batch}))
+		notes := make([]api.Message, 0, len(batches))
+		for i, items := range batches {
+			if progress != nil {
+				progress(i, len(batches)+1)
+			}
+			result, err := api.AskWithSmallModel(api.WithPurpose(ctx, "digest-batch"), append([]api.Message{batch}, items...))
+			if err != nil {
+				return "", err
+			}
+			notes = append(notes, api.Message{
+				Role:    "user",
+				Content: fmt.Sprintf("Notes %d/%d:\n%s", i+1, len(batches), strings.TrimSpace(result)),
+			})
+		}
+		if progress != nil {
+			progress(len(batches), len(batches)+1)
+		}
+		messages = notes
+	}
+
+	return api.StreamWithSmallModel(ctx, append([]api.Message{system}, messages...), onDelta)
+}
+
+// 概要只使用 RSS 摘要；被付費牆或同意頁擋住的文章沒有摘要時排除，避免擷取到的提示文字混入
+func articleMessage(item model.News) (api.Message, bool) {
+	content := strings.TrimSpace(item.Content)
+	if item.IsBlocked() && content == "" {
+		return api.Message{}, false
+	}
+	// 標註原文語言，讓模型知道需要翻譯的來源
+	language := ""
+	if item.Language != nil {
+		language = fmt.Sprintf("Language: %s\n", *item.Language)
+	}
+	return api.Message{
+		Role:    "user",
+		Content: fmt.Sprintf("Title: %s\nSource: %s\nPublishedAt: %s\n%sContent: %s", item.Title, item.Source, item.PublishedAt.Local().Format("2006-01-02 15:04"), language, content),
+	}, true
+}
+
+// 產生單篇文章的條列概要並儲存，onDelta 依序收到產生的內容
+func (s *Summarizer) Article(ctx context.Context, news model.News, onDelta func(string)) (string, error) {
+	content := ""
+	if news.FullContent != nil && !news.IsBlocked() {
+		content = strings.TrimSpace(*news.FullContent)
+	}
+	// 被擋住或沒有正文時改用 RSS 摘要
+	if content == "" {
+		content = strings.TrimSpace(news.Content)
+	}
+	if content == "" {
+		return "", errors.New("article has no content to summarize")
+	}
+	// 條列概要的語言跟隨目前的範本
+	name := s.PromptName(ctx)
+	text, err := LoadPrompt(name)
+	if err != nil {
+		return "", err
+	}
+	articlePrompt, err := renderPrompt(name, text, "article", newPromptData("", nil))
+	if err != nil {
+		return "", err
+	}
+	ctx = loadAPIKey(ctx, s.db)
+	ctx = api.WithPurpose(ctx, "article")
+
+	message, _ := articleMessage(model.News{
+		Title:       news.Title,
+		Source:      news.Source,
+		PublishedAt: news.PublishedAt,
+		Language:    news.Language,
+		Content:     content,
+	})
+	result, err := api.StreamWithSmallModel(ctx, []api.Message{
+		{Role: "system", Content: articlePrompt},
+		message,
+	}, onDelta)
+	if err != nil {
+		return "", err
+	}
+
+	result = strings.TrimSpace(result)
+	// daemon 執行中時不快取
+	if err := s.db.SetSummary(ctx, news.URL, result); err != nil && !errors.Is(err, database.ErrReadOnly) {
+		return "", err
+	}
+	return result, nil
+}
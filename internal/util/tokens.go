@@ -0,0 +1,58 @@
+package util
+
+import (
+	"rss-reader/internal/api"
+)
+
+// 每則訊息的角色與格式標記約佔的 token 數
+const messageOverhead = 4
+
+// 粗估 token 數：英文約 0.75 字一個 token，中日文約一個字元一個 token，
+// 估算偏高以保留餘裕
+func estimateTokens(str string) int {
+	words, cjk := countWords(str)
+	return (words*4+2)/3 + cjk
+}
+
+func estimateMessages(messages []api.Message) int {
+	total := 0
+	for _, msg := range messages {
+		total += estimateTokens(msg.Content) + messageOverhead
+	}
+	return total
+}
+
+// 依 token 預算將訊息分批，單則超過預算時截斷內容
+func batchMessages(messages []api.Message, budget int) [][]api.Message {
+	var batches [][]api.Message
+	var batch []api.Message
+	used := 0
+
+	for _, msg := range messages {
+		tokens := estimateTokens(msg.Content) + messageOverhead
+		if tokens > budget {
+			msg.Content = truncateTokens(msg.Content, budget-messageOverhead)
+			tokens = budget
+		}
+		if len(batch) > 0 && used+tokens > budget {
+			batches = append(batches, batch)
+			batch, used = nil, 0
+		}
+		batch = append(batch, msg)
+		used += tokens
+	}
+	if len(batch) > 0 {
+		batches = append(batches, batch)
+	}
+	return batches
+}
+
+// 依估算比例截斷文字
+func truncateTokens(str string, limit int) string {
+	tokens := estimateTokens(str)
+	if tokens <= limit {
+		return str
+	}
+	runes := []rune(str)
+	return string(runes[:len(runes)*max(limit, 0)/tokens])
+}
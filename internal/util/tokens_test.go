@@ -0,0 +1,88 @@
+package util
+
+import (
+	"strings"
+	"testing"
+
+	"rss-reader/internal/api"
+)
+
+func TestTruncateTokens(t *testing.T) {
+	tests := []struct {
+		name  string
+		in    string
+		limit int
+		want  string
+	}{
+		{"within limit", "one two three", 10, "one two three"},
+		{"chinese", "一二三四五六七八九十", 5, "一二三四五"},
+		{"zero limit", "一二三四", 0, ""},
+		{"negative limit", "一二三四", -3, ""},
+		{"empty", "", 0, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := truncateTokens(tt.in, tt.limit); got != tt.want {
+				t.Errorf("truncateTokens(%q, %d) = %q, want %q", tt.in, tt.limit, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBatchMessages(t *testing.T) {
+	// 每則 10 個中文字，估算為 10 + 4 個 token
+	msg := func(name string) api.Message {
+		return api.Message{Role: "user", Content: strings.Repeat(name, 10)}
+	}
+
+	tests := []struct {
+		name     string
+		messages []api.Message
+		budget   int
+		want     [][]string
+	}{
+		{
+			name: "empty",
+			want: nil,
+		},
+		{
+			name:     "all fit",
+			messages: []api.Message{msg("甲"), msg("乙")},
+			budget:   100,
+			want:     [][]string{{"甲", "乙"}},
+		},
+		{
+			name:     "split by budget",
+			messages: []api.Message{msg("甲"), msg("乙"), msg("丙")},
+			budget:   28,
+			want:     [][]string{{"甲", "乙"}, {"丙"}},
+		},
+		{
+			name:     "oversized message truncated",
+			messages: []api.Message{msg("甲"), msg("乙")},
+			budget:   9,
+			want:     [][]string{{"甲"}, {"乙"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			batches := batchMessages(tt.messages, tt.budget)
+			if len(batches) != len(tt.want) {
+				t.Fatalf("got %d batches, want %d", len(batches), len(tt.want))
+			}
+			for i, batch := range batches {
+				if len(batch) != len(tt.want[i]) {
+					t.Fatalf("batch %d has %d messages, want %d", i, len(batch), len(tt.want[i]))
+				}
+				for j, m := range batch {
+					if !strings.HasPrefix(m.Content, tt.want[i][j]) {
+						t.Errorf("batch %d message %d = %q, want %s...", i, j, m.Content, tt.want[i][j])
+					}
+					if used := estimateMessages(batch); used > tt.budget {
+						t.Errorf("batch %d uses %d tokens, budget %d", i, used, tt.budget)
+					}
+				}
+			}
+		})
+	}
+}
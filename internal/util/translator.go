@@ -0,0 +1,134 @@
+package util
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"rss-reader/internal/api"
+	"rss-reader/internal/database"
+	"rss-reader/internal/model"
+)
+
+// 每次送出的字元數上限，以段落為單位切分
+const chunkSize = 3000
+
+var languageNames = map[string]string{
+	"zh":      "Traditional Chinese",
+	"zh-TW":   "Traditional Chinese (Taiwan)",
+	"zh-Hant": "Traditional Chinese",
+	"zh-HK":   "Traditional Chinese (Hong Kong)",
+	"zh-CN":   "Simplified Chinese",
+	"zh-Hans": "Simplified Chinese",
+	"en":      "English",
+	"ja":      "Japanese",
+	"ko":      "Korean",
+	"fr":      "French",
+	"de":      "German",
+	"es":      "Spanish",
+}
+
+type Translator struct {
+	db *database.SQLite
+}
+
+func NewTranslator(db *database.SQLite) *Translator {
+	return &Translator{db: db}
+}
+
+// 未指定目標語言時，中文文章翻成英文，其他翻成繁體中文
+func TranslateTarget(news model.News, target string) string {
+	if target != "" {
+		return normalizeLanguage(target)
+	}
+	if news.Language != nil && PrimaryLanguage(*news.Language) == "zh" {
+		return "en"
+	}
+	return "zh-TW"
+}
+
+// 分段翻譯文章完整內容，onDelta 依序收到翻譯結果；完成後快取，中止時不快取
+func (t *Translator) Translate(ctx context.Context, news model.News, target string, onDelta func(string)) (string, error) {
+	if cached, err := t.db.GetTranslation(ctx, news.URL, target); err == nil && cached != "" {
+		onDelta(cached)
+		return cached, nil
+	}
+
+	if news.FullContent == nil || strings.TrimSpace(*news.FullContent) == "" {
+		return "", errors.New("article has no content to translate")
+	}
+	ctx = loadAPIKey(ctx, t.db)
+	ctx = api.WithPurpose(ctx, "translate")
+
+	source := "the original language"
+	if news.Language != nil {
+		source = languageName(*news.Language)
+	}
+	system := fmt.Sprintf(`You are a professional news translator. Translate the Markdown the user sends from %s into %s.
+Keep the Markdown structure, names, numbers and quotes. Output only the translation without any explanation.`, source, languageName(target))
+
+	var result strings.Builder
+	for i, chunk := range splitChunks(*news.FullContent, chunkSize) {
+		if i > 0 {
+			result.WriteString("\n\n")
+			onDelta("\n\n")
+		}
+		text, err := api.StreamWithSmallModel(ctx, []api.Message{
+			{Role: "system", Content: system},
+			{Role: "user", Content: chunk},
+		}, onDelta)
+		if err != nil {
+			return "", err
+		}
+		result.WriteString(strings.TrimSpace(text))
+	}
+
+	translation := result.String()
+	if err := t.db.SetTranslation(ctx, news.URL, target, translation); err != nil && !errors.Is(err, database.ErrReadOnly) {
+		return "", err
+	}
+	return translation, nil
+}
+
+func languageName(code string) string {
+	code = normalizeLanguage(code)
+	if name, ok := languageNames[code]; ok {
+		return name
+	}
+	if name, ok := languageNames[PrimaryLanguage(code)]; ok {
+		return name
+	}
+	return code
+}
+
+// 依段落組合成不超過 size 字元的區塊，單一段落過長時獨立成一塊
+func splitChunks(content string, size int) []string {
+	var chunks []string
+	var current strings.Builder
+
+	for _, block := range strings.Split(content, "\n\n") {
+		if strings.TrimSpace(block) == "" {
+			continue
+		}
+		if current.Len() > 0 && utf8.RuneCountInString(current.String())+utf8.RuneCountInString(block) > size {
+			chunks = append(chunks, current.String())
+			current.Reset()
+		}
+		if current.Len() > 0 {
+			current.WriteString("\n\n")
+		}
+		current.WriteString(block)
+	}
+	if current.Len() > 0 {
+		chunks = append(chunks, current.String())
+	}
+	return chunks
+}
+
+// 從資料庫載入 API key 放入 context，並以同一個資料庫記錄用量
+func loadAPIKey(ctx context.Context, db *database.SQLite) context.Context {
+	key, _ := db.GetKey(ctx, "apikey")
+	return api.WithMeter(api.WithAPIKey(ctx, key), &usageMeter{db: db})
+}
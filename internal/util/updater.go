@@ -0,0 +1,220 @@
+package util
+
+import (
+	"context"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"rss-reader/internal/config"
+	"rss-reader/internal/database"
+	"rss-reader/internal/model"
+)
+
+// 累積一定數量後以單一交易寫入，減少鎖競爭
+const batchSize = 10
+
+type Updater struct {
+	db        *database.SQLite
+	collector *Collector
+	extractor *Extractor
+	archiver  *Archiver
+}
+
+func NewUpdater(db *database.SQLite, collector *Collector, extractor *Extractor) *Updater {
+	return &Updater{
+		db:        db,
+		collector: collector,
+		extractor: extractor,
+		archiver:  NewArchiver(db),
+	}
+}
+
+// 從 RSS 取得文章，回傳依發布時間排序的列表與資料庫中尚未存在的文章數
+func (u *Updater) Check(ctx context.Context) ([]model.News, int, error) {
+	newArticles, err := u.collector.GetNews(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// 上次中止時留在佇列中的文章也需要擷取
+	queued, err := u.db.Queue(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+	leftover := make(map[string]bool, len(queued))
+	for _, item := range queued {
+		leftover[item.News.URL] = true
+	}
+
+	newCount := 0
+	finalArticles := make([]model.News, 0, len(newArticles))
+
+	for _, article := range newArticles {
+		delete(leftover, article.URL)
+		stored, err := u.db.GetFromURL(ctx, article.URL)
+		if err != nil {
+			// 資料庫沒有這篇文章，計入新文章
+			newCount++
+		} else {
+			// 使用資料庫中的發布時間與擷取結果
+			article.PublishedAt = stored.PublishedAt
+			article.Status = stored.Status
+			article.ReadingTime = stored.ReadingTime
+			article.Language = stored.Language
+		}
+		finalArticles = append(finalArticles, article)
+	}
+
+	if err := ctx.Err(); err != nil {
+		return nil, 0, err
+	}
+	newCount += len(leftover)
+
+	// 按發布時間排序 (新到舊)
+	sort.Slice(finalArticles, func(i, j int) bool {
+		return finalArticles[i].PublishedAt.After(finalArticles[j].PublishedAt)
+	})
+
+	return finalArticles, newCount, nil
+}
+
+// 擷取尚無完整內容的文章並寫入資料庫，progress 可為 nil；
+// 文章先寫入擷取佇列，中止或重新啟動後會繼續未完成的部分
+func (u *Updater) Load(ctx context.Context, news []model.News, progress func(current, total int)) error {
+	var pending []model.News
+	for _, article := range news {
+		stored, err := u.db.GetFromURL(ctx, article.URL)
+		if err == nil && stored.IsExtracted() {
+			continue
+		}
+		pending = append(pending, article)
+	}
+
+	if err := u.db.Enqueue(ctx, pending); err != nil {
+		return err
+	}
+	items, err := u.db.Queue(ctx)
+	if err != nil {
+		return err
+	}
+	if len(items) == 0 {
+		return nil
+	}
+
+	cfg := config.Get().Extractor
+	limiter := newHostLimiter(cfg.Delay)
+	jobs := make(chan database.QueueItem)
+	results := make(chan database.Entry)
+
+	var wg sync.WaitGroup
+	for i := 0; i < min(cfg.Workers, len(items)); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for item := range jobs {
+				if entry, ok := u.extract(ctx, limiter, item); ok {
+					results <- entry
+				}
+			}
+		}()
+	}
+
+	go func() {
+		defer close(jobs)
+		for _, item := range interleave(items) {
+			select {
+			case jobs <- item:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	// 已擷取的內容即使中止仍會寫入
+	storeCtx := context.WithoutCancel(ctx)
+	batch := make([]database.Entry, 0, batchSize)
+	flush := func() {
+		if err := u.db.InsertBatch(storeCtx, batch); err != nil {
+			log.Printf("Failed to store news batch: %v", err)
+		}
+		batch = batch[:0]
+	}
+
+	current := 0
+	for entry := range results {
+		batch = append(batch, entry)
+		if len(batch) >= batchSize {
+			flush()
+		}
+
+		current++
+		if progress != nil {
+			progress(current, len(items))
+		}
+	}
+	flush()
+
+	return ctx.Err()
+}
+
+// 擷取單篇文章，暫時性錯誤依退避時間重試；中止時回傳 false，文章留在佇列中
+func (u *Updater) extract(ctx context.Context, limiter *hostLimiter, item database.QueueItem) (database.Entry, bool) {
+	article := item.News
+	host := hostname(article.URL)
+	retries := config.Get().Extractor.Retries
+
+	var extracted *model.NewsContent
+	for attempt := item.Attempts; ; attempt++ {
+		if err := limiter.wait(ctx, host); err != nil {
+			return database.Entry{}, false
+		}
+
+		content, err := u.extractor.Extract(ctx, article)
+		if ctx.Err() != nil {
+			return database.Entry{}, false
+		}
+		extracted = content
+		if err == nil {
+			break
+		}
+		if !transient(err) || attempt >= retries {
+			log.Printf("Failed to get content %s: %v", article.URL, err)
+			break
+		}
+
+		if err := u.db.UpdateQueue(ctx, article.URL, attempt+1, err.Error()); err != nil {
+			log.Printf("Failed to update queue %s: %v", article.URL, err)
+		}
+		select {
+		case <-ctx.Done():
+			return database.Entry{}, false
+		case <-time.After(backoff(attempt)):
+		}
+	}
+
+	// 封存模式下同時保存離線快照，失敗不影響內容寫入；
+	// 快照的請求同樣經過 limiter，遵守每個網站的請求間隔
+	if extracted.Status == model.StatusSuccess && config.Get().Archive.Enabled {
+		if _, err := u.archiver.archive(ctx, article.URL, limiter); err != nil && ctx.Err() == nil {
+			log.Printf("Failed to archive %s: %v", article.URL, err)
+		}
+	}
+
+	ApplyPubDate(&article, extracted)
+	return database.Entry{News: article, Content: extracted}, true
+}
+
+// RSS 沒有發布時間時，改用頁面上標示的發布時間
+func ApplyPubDate(news *model.News, content *model.NewsContent) {
+	if news.NoPubDate && content != nil && !content.PublishedAt.IsZero() {
+		news.PublishedAt = content.PublishedAt
+		news.NoPubDate = false
+	}
+}
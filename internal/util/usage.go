@@ -0,0 +1,160 @@
+package util
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"rss-reader/internal/api"
+	"rss-reader/internal/config"
+	"rss-reader/internal/database"
+)
+
+// 記錄每次 LLM 呼叫的用量，超過每月預算時阻擋後續呼叫
+type usageMeter struct {
+	db *database.SQLite
+}
+
+func (m *usageMeter) Check(ctx context.Context) error {
+	budget := config.Get().LLM.MonthlyBudget
+	if budget <= 0 {
+		return nil
+	}
+	totals, err := m.db.UsageTotals(ctx, "%Y-%m", monthStart(time.Now()))
+	if err != nil {
+		return err
+	}
+	spent := 0.0
+	for _, total := range totals {
+		cost, _ := usageCost(total)
+		spent += cost
+	}
+	if spent >= budget {
+		return &api.Error{
+			Kind:    api.ErrBudget,
+			Message: fmt.Sprintf("spent $%.2f of $%.2f this month", spent, budget),
+		}
+	}
+	return nil
+}
+
+func (m *usageMeter) Record(ctx context.Context, call api.Call) {
+	err := m.db.AddUsage(ctx, database.Usage{
+		Provider:         call.Provider,
+		Model:            call.Model,
+		Purpose:          call.Purpose,
+		PromptTokens:     call.Usage.PromptTokens,
+		CompletionTokens: call.Usage.CompletionTokens,
+	})
+	// daemon 執行中時由 daemon 記錄，不重複寫入
+	if err != nil && !errors.Is(err, database.ErrReadOnly) {
+		log.Printf("Failed to record usage: %v", err)
+	}
+}
+
+type UsageRow struct {
+	database.UsageTotal
+	Cost float64 `json:"cost"`
+	// 價格表沒有此模型時為 false
+	Priced bool `json:"priced"`
+}
+
+type UsageReport struct {
+	// 本月每日與近 12 個月的用量
+	Days   []UsageRow `json:"days"`
+	Months []UsageRow `json:"months"`
+	Spent  float64    `json:"spent"`
+	Budget float64    `json:"budget"`
+}
+
+func NewUsageReport(ctx context.Context, db *database.SQLite) (*UsageReport, error) {
+	now := time.Now()
+	start := monthStart(now)
+
+	days, err := db.UsageTotals(ctx, "%Y-%m-%d", start)
+	if err != nil {
+		return nil, err
+	}
+	months, err := db.UsageTotals(ctx, "%Y-%m", start.AddDate(0, -11, 0))
+	if err != nil {
+		return nil, err
+	}
+
+	report := &UsageReport{
+		Days:   usageRows(days),
+		Months: usageRows(months),
+		Budget: config.Get().LLM.MonthlyBudget,
+	}
+	current := now.Format("2006-01")
+	for _, row := range report.Months {
+		if row.Period == current {
+			report.Spent += row.Cost
+		}
+	}
+	return report, nil
+}
+
+func (r *UsageReport) String() string {
+	var b strings.Builder
+	if r.Budget > 0 {
+		fmt.Fprintf(&b, "This month: $%.4f of $%.2f\n\n", r.Spent, r.Budget)
+	} else {
+		fmt.Fprintf(&b, "This month: $%.4f\n\n", r.Spent)
+	}
+
+	w := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+	for _, section := range []struct {
+		name string
+		rows []UsageRow
+	}{
+		{"Month", r.Months},
+		{"Day", r.Days},
+	} {
+		fmt.Fprintf(w, "%s\tModel\tCalls\tPrompt\tCompletion\tCost\t\n", section.name)
+		for _, row := range section.rows {
+			cost := "-"
+			if row.Priced {
+				cost = fmt.Sprintf("$%.4f", row.Cost)
+			}
+			fmt.Fprintf(w, "%s\t%s\t%d\t%d\t%d\t%s\t\n", row.Period, row.Model, row.Calls, row.PromptTokens, row.CompletionTokens, cost)
+		}
+		fmt.Fprintln(w, "\t\t\t\t\t\t")
+	}
+	w.Flush()
+	return strings.TrimRight(b.String(), " \n") + "\n"
+}
+
+func usageRows(totals []database.UsageTotal) []UsageRow {
+	rows := make([]UsageRow, 0, len(totals))
+	for _, total := range totals {
+		cost, ok := usageCost(total)
+		rows = append(rows, UsageRow{UsageTotal: total, Cost: cost, Priced: ok})
+	}
+	return rows
+}
+
+// 依價格表估算花費，沒有完全相符的模型時採用最長的前綴（如 gpt-4o-mini-2024-07-18）
+func usageCost(total database.UsageTotal) (float64, bool) {
+	prices := config.Get().LLM.Prices
+	price, ok := prices[total.Model]
+	if !ok {
+		matched := ""
+		for name, p := range prices {
+			if strings.HasPrefix(total.Model, name) && len(name) > len(matched) {
+				matched, price, ok = name, p, true
+			}
+		}
+	}
+	if !ok {
+		return 0, false
+	}
+	return (float64(total.PromptTokens)*price.Input + float64(total.CompletionTokens)*price.Output) / 1e6, true
+}
+
+func monthStart(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location())
+}
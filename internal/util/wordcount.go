@@ -0,0 +1,66 @@
+package util
+
+import (
+	"math"
+	"unicode"
+)
+
+// 閱讀速度：空白分隔的文字以字計，中文與日文以字元計
+const (
+	wordsPerMinute = 230
+	cjkPerMinute   = 400
+)
+
+// 依 Unicode 分類計算字數：漢字與假名每個字元算一字，
+// 其餘文字（拉丁、諺文、西里爾等）以連續的字母、數字與組合符號為一字
+func countWords(str string) (words, cjk int) {
+	runes := []rune(str)
+	inWord := false
+
+	for i, r := range runes {
+		switch {
+		case isCJK(r):
+			cjk++
+			inWord = false
+		case unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.IsMark(r):
+			if !inWord {
+				words++
+				inWord = true
+			}
+		case inWord && isJoiner(runes, i):
+			// don't、e-mail、3.14 視為一字
+		default:
+			inWord = false
+		}
+	}
+	return words, cjk
+}
+
+func isCJK(r rune) bool {
+	return unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana) || r == 'ー'
+}
+
+// 字內的撇號、連字號與數字間的小數點、千分位
+func isJoiner(runes []rune, i int) bool {
+	if i+1 >= len(runes) {
+		return false
+	}
+	prev, next := runes[i-1], runes[i+1]
+	switch runes[i] {
+	case '\'', '’', '-':
+		return unicode.IsLetter(next)
+	case '.', ',':
+		return unicode.IsDigit(prev) && unicode.IsDigit(next)
+	}
+	return false
+}
+
+// 預估閱讀分鐘數，有內容時至少 1 分鐘
+func readingTime(str string) int {
+	words, cjk := countWords(str)
+	if words+cjk == 0 {
+		return 0
+	}
+	minutes := float64(words)/wordsPerMinute + float64(cjk)/cjkPerMinute
+	return max(1, int(math.Round(minutes)))
+}
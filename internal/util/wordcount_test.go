@@ -0,0 +1,56 @@
+package util
+
+import "testing"
+
+func TestCountWords(t *testing.T) {
+	tests := []struct {
+		in         string
+		words, cjk int
+	}{
+		{"", 0, 0},
+		{"The quick brown fox", 4, 0},
+		{"don't use e-mail", 3, 0},
+		{"pi is 3.14, not 1,000", 5, 0},
+		{"颱風逼近北部", 0, 6},
+		{"東京タワー", 0, 5},
+		{"서울 날씨 맑음", 3, 0},
+		{"Apple 發表 iPhone 新機", 2, 4},
+		{"  -- ... ", 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			words, cjk := countWords(tt.in)
+			if words != tt.words || cjk != tt.cjk {
+				t.Errorf("countWords(%q) = %d, %d, want %d, %d", tt.in, words, cjk, tt.words, tt.cjk)
+			}
+		})
+	}
+}
+
+func TestReadingTime(t *testing.T) {
+	repeat := func(s string, n int) string {
+		out := ""
+		for i := 0; i < n; i++ {
+			out += s
+		}
+		return out
+	}
+	tests := []struct {
+		name string
+		in   string
+		want int
+	}{
+		{"empty", "", 0},
+		{"short text rounds up to one", "hello world", 1},
+		{"english", repeat("word ", 690), 3},
+		{"chinese", repeat("字", 800), 2},
+		{"mixed", repeat("word ", 230) + repeat("字", 400), 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := readingTime(tt.in); got != tt.want {
+				t.Errorf("readingTime = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}